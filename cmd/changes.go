@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dtan4/k8sec/pkg/dotenv"
+)
+
+const maskedValue = "********"
+
+// dataChanges represents the differences between two sets of secret data
+type dataChanges struct {
+	added   []string
+	changed []string
+	removed []string
+}
+
+// diffData compares the data of secrets and returns changed keys sorted in alphabetical order
+func diffData(oldData, newData map[string][]byte) dataChanges {
+	changes := dataChanges{}
+
+	for k, v := range newData {
+		ov, ok := oldData[k]
+		if !ok {
+			changes.added = append(changes.added, k)
+		} else if !bytes.Equal(ov, v) {
+			changes.changed = append(changes.changed, k)
+		}
+	}
+
+	for k := range oldData {
+		if _, ok := newData[k]; !ok {
+			changes.removed = append(changes.removed, k)
+		}
+	}
+
+	sort.Strings(changes.added)
+	sort.Strings(changes.changed)
+	sort.Strings(changes.removed)
+
+	return changes
+}
+
+// empty reports whether there is no change
+func (c dataChanges) empty() bool {
+	return len(c.added) == 0 && len(c.changed) == 0 && len(c.removed) == 0
+}
+
+// printDataChanges prints changed keys with "+" (added), "~" (changed) and "-" (removed) marks
+func printDataChanges(out io.Writer, changes dataChanges) {
+	for _, k := range changes.added {
+		fmt.Fprintln(out, "+ "+k)
+	}
+
+	for _, k := range changes.changed {
+		fmt.Fprintln(out, "~ "+k)
+	}
+
+	for _, k := range changes.removed {
+		fmt.Fprintln(out, "- "+k)
+	}
+}
+
+// printDataDiff prints changed keys like printDataChanges, with their values masked unless showValues is true
+func printDataDiff(out io.Writer, oldData, newData map[string][]byte, changes dataChanges, showValues bool) {
+	format := func(v []byte) string {
+		if !showValues {
+			return maskedValue
+		}
+
+		return dotenv.Quote(string(v))
+	}
+
+	for _, k := range changes.added {
+		fmt.Fprintf(out, "+ %s=%s\n", k, format(newData[k]))
+	}
+
+	for _, k := range changes.changed {
+		fmt.Fprintf(out, "~ %s=%s => %s\n", k, format(oldData[k]), format(newData[k]))
+	}
+
+	for _, k := range changes.removed {
+		fmt.Fprintf(out, "- %s=%s\n", k, format(oldData[k]))
+	}
+}
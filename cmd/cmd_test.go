@@ -2,16 +2,39 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/dtan4/k8sec/pkg/client"
 	v1 "k8s.io/api/core/v1"
 )
 
 type fakeClient struct {
+	contextName          string
 	defaultNamespace     string
 	getSecretResponse    *v1.Secret
 	listSecretsResponse  *v1.SecretList
 	updateSecretResponse *v1.Secret
 	err                  error
+
+	// getSecretErr is returned by GetSecret instead of err if set
+	getSecretErr error
+
+	// secrets passed to CreateSecret and UpdateSecret
+	createdSecret *v1.Secret
+	updatedSecret *v1.Secret
+	// createdSecrets are all secrets passed to CreateSecret, and createdNamespaces are their namespaces
+	createdSecrets    []*v1.Secret
+	createdNamespaces []string
+
+	// deleteSecretErrs are returned by DeleteSecret for the secret names instead of err
+	deleteSecretErrs map[string]error
+	// names and options passed to DeleteSecret
+	deletedSecrets []string
+	deleteOptions  client.DeleteOptions
+}
+
+func (c *fakeClient) ContextName() string {
+	return c.contextName
 }
 
 func (c *fakeClient) DefaultNamespace() string {
@@ -19,10 +42,28 @@ func (c *fakeClient) DefaultNamespace() string {
 }
 
 func (c *fakeClient) CreateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
+	c.createdSecret = secret
+	c.createdSecrets = append(c.createdSecrets, secret)
+	c.createdNamespaces = append(c.createdNamespaces, namespace)
 	return secret, c.err
 }
 
+func (c *fakeClient) DeleteSecret(ctx context.Context, namespace, name string, opts client.DeleteOptions) error {
+	if err, ok := c.deleteSecretErrs[name]; ok {
+		return err
+	}
+
+	c.deletedSecrets = append(c.deletedSecrets, name)
+	c.deleteOptions = opts
+
+	return c.err
+}
+
 func (c *fakeClient) GetSecret(ctx context.Context, namespace, name string) (*v1.Secret, error) {
+	if c.getSecretErr != nil {
+		return nil, c.getSecretErr
+	}
+
 	return c.getSecretResponse, c.err
 }
 
@@ -31,5 +72,18 @@ func (c *fakeClient) ListSecrets(ctx context.Context, namespace string) (*v1.Sec
 }
 
 func (c *fakeClient) UpdateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
+	c.updatedSecret = secret
 	return c.updateSecretResponse, c.err
 }
+
+// newFakeClientFactory returns clientFactory which returns the fake client for each context
+func newFakeClientFactory(clients map[string]*fakeClient) clientFactory {
+	return func(kubeContext string) (client.Client, error) {
+		c, ok := clients[kubeContext]
+		if !ok {
+			return nil, fmt.Errorf("context %q does not exist", kubeContext)
+		}
+
+		return c, nil
+	}
+}
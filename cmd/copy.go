@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type copyOpts struct {
+	keys            []string
+	keepLabels      []string
+	keepAnnotations []string
+	overwrite       bool
+	update          updateOpts
+}
+
+func newCopyCmd(out io.Writer) *cobra.Command {
+	opts := copyOpts{}
+
+	copyCmd := &cobra.Command{
+		Use:   "copy SRC DST",
+		Short: "Copy secret to another namespace or cluster",
+		Long: `Copy secret to another namespace or cluster
+
+Each secret can be specified in [CONTEXT:][NAMESPACE/]NAME format. Namespace defaults to the one given by --namespace
+flag, or the default namespace of the context:
+
+$ k8sec copy staging/rails production/rails
+$ k8sec copy staging-cluster:app/rails production-cluster:app/rails
+
+The type of the secret is kept, while server-populated metadata such as uid, resourceVersion and ownerReferences are
+not copied. Labels and annotations are copied only if they are specified. "*" copies all of them:
+
+$ k8sec copy --keep-label app --keep-annotation '*' staging/rails production/rails
+
+Copy the specified keys only. Keys can be renamed with OLD=NEW:
+
+$ k8sec copy --key database-url=DATABASE_URL --key rails-env staging/rails production/rails
+
+The destination secret must not exist unless --overwrite is given. With --overwrite, its data is replaced with the copied
+one.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("source and destination secrets must be specified")
+			}
+
+			ctx := context.Background()
+
+			return runCopy(ctx, newClientFactory(out), rootOpts.namespace, args, out, &opts)
+		},
+	}
+
+	copyCmd.Flags().StringArrayVar(&opts.keys, "key", []string{}, "Key to copy, optionally renamed in OLD=NEW format. All keys are copied if not specified")
+	copyCmd.Flags().StringArrayVar(&opts.keepLabels, "keep-label", []string{}, `Label key to copy. "*" copies all labels`)
+	copyCmd.Flags().StringArrayVar(&opts.keepAnnotations, "keep-annotation", []string{}, `Annotation key to copy. "*" copies all annotations`)
+	copyCmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Overwrite the destination secret if it exists")
+	addUpdateFlags(copyCmd.Flags(), &opts.update)
+
+	return copyCmd
+}
+
+// runCopy copies the source secret to the destination. namespace is used for secrets without namespace, and can be
+// empty.
+func runCopy(ctx context.Context, newClient clientFactory, namespace string, args []string, out io.Writer, opts *copyOpts) error {
+	src, err := parseSecretRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseSecretRef(args[1])
+	if err != nil {
+		return err
+	}
+
+	srcClient, srcNamespace, err := resolveSecretRef(newClient, src, namespace)
+	if err != nil {
+		return err
+	}
+
+	dstClient, dstNamespace, err := resolveSecretRef(newClient, dst, namespace)
+	if err != nil {
+		return err
+	}
+
+	// Empty context means the current context, so compare the contexts resolved by clients
+	if srcClient.ContextName() == dstClient.ContextName() && srcNamespace == dstNamespace && src.name == dst.name {
+		return fmt.Errorf("source and destination are the same secret %q", src)
+	}
+
+	s, err := srcClient.GetSecret(ctx, srcNamespace, src.name)
+	if err != nil {
+		return fmt.Errorf("get secret %q: %w", src, err)
+	}
+
+	data, err := selectData(s.Data, opts.keys)
+	if err != nil {
+		return fmt.Errorf("secret %q: %w", src, err)
+	}
+
+	secret := stripSecret(s, dst.name, opts.keepLabels, opts.keepAnnotations)
+	secret.Data = data
+
+	existing, err := dstClient.GetSecret(ctx, dstNamespace, dst.name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get secret %q: %w", dst, err)
+		}
+
+		existing = nil
+	}
+
+	if existing == nil {
+		if opts.update.ifResourceVersion != "" {
+			return fmt.Errorf("secret %q does not exist", dst)
+		}
+
+		if _, err := dstClient.CreateSecret(ctx, dstNamespace, secret); err != nil {
+			return fmt.Errorf("create secret %q: %w", dst, err)
+		}
+	} else {
+		if !opts.overwrite {
+			return fmt.Errorf("secret %q already exists, use --overwrite to replace it", dst)
+		}
+
+		if existing.Type != secret.Type {
+			return fmt.Errorf("cannot overwrite secret %q of type %q with type %q", dst, existing.Type, secret.Type)
+		}
+
+		_, err = updateSecret(ctx, dstClient, dstNamespace, existing, &opts.update, func(s *v1.Secret) error {
+			s.Data = secret.Data
+
+			for k, v := range secret.Labels {
+				if s.Labels == nil {
+					s.Labels = map[string]string{}
+				}
+
+				s.Labels[k] = v
+			}
+
+			for k, v := range secret.Annotations {
+				if s.Annotations == nil {
+					s.Annotations = map[string]string{}
+				}
+
+				s.Annotations[k] = v
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("set secret %q: %w", dst, err)
+		}
+	}
+
+	if !isDryRun(dstClient) {
+		fmt.Fprintf(out, "secret %q copied to %q\n", src, dst)
+	}
+
+	return nil
+}
+
+// selectData returns the data with the given keys only. Each key can be renamed in OLD=NEW format.
+// All data is returned if keys is empty.
+func selectData(data map[string][]byte, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return data, nil
+	}
+
+	selected := make(map[string][]byte, len(keys))
+
+	for _, key := range keys {
+		oldKey, newKey, ok := strings.Cut(key, "=")
+		if !ok {
+			newKey = oldKey
+		}
+
+		if oldKey == "" || newKey == "" {
+			return nil, fmt.Errorf("key %q must be in KEY or OLD=NEW format", key)
+		}
+
+		v, ok := data[oldKey]
+		if !ok {
+			return nil, fmt.Errorf("the key %s does not exist", oldKey)
+		}
+
+		if _, ok := selected[newKey]; ok {
+			return nil, fmt.Errorf("the key %s is specified more than once", newKey)
+		}
+
+		selected[newKey] = v
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRunCopy(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "rails",
+			Namespace:       "staging",
+			UID:             types.UID("12345678-1234-1234-1234-123456789012"),
+			ResourceVersion: "12345",
+			Labels: map[string]string{
+				"app":  "rails",
+				"tier": "backend",
+			},
+			Annotations: map[string]string{
+				"owner":                     "dtan4",
+				lastAppliedConfigAnnotation: "{}",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					Kind: "SealedSecret",
+					Name: "rails",
+				},
+			},
+		},
+		Type: "example.com/custom",
+		Data: map[string][]byte{
+			"database-url": []byte("postgres://example.com:5432/dbname"),
+			"rails-env":    []byte("staging"),
+		},
+	}
+
+	notFound := apierrors.NewNotFound(v1.Resource("secrets"), "rails")
+
+	testcases := map[string]struct {
+		args             []string
+		opts             copyOpts
+		production       *v1.Secret
+		productionGetErr error
+		wantCreated      *v1.Secret
+		wantUpdated      *v1.Secret
+		wantOut          string
+		wantErr          error
+	}{
+		"copy to another cluster": {
+			args: []string{"staging/rails", "production:app/rails"},
+			opts: copyOpts{
+				keepLabels:      []string{"app", "unknown"},
+				keepAnnotations: []string{"*"},
+			},
+			productionGetErr: notFound,
+			wantCreated: &v1.Secret{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Secret",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+					Labels: map[string]string{
+						"app": "rails",
+					},
+					Annotations: map[string]string{
+						"owner": "dtan4",
+					},
+				},
+				Type: "example.com/custom",
+				Data: map[string][]byte{
+					"database-url": []byte("postgres://example.com:5432/dbname"),
+					"rails-env":    []byte("staging"),
+				},
+			},
+			wantOut: `secret "staging/rails" copied to "production:app/rails"
+`,
+		},
+
+		"copy selected keys": {
+			args: []string{"staging/rails", "production:app/rails"},
+			opts: copyOpts{
+				keys: []string{"database-url=DATABASE_URL", "rails-env"},
+			},
+			productionGetErr: notFound,
+			wantCreated: &v1.Secret{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Secret",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Type: "example.com/custom",
+				Data: map[string][]byte{
+					"DATABASE_URL": []byte("postgres://example.com:5432/dbname"),
+					"rails-env":    []byte("staging"),
+				},
+			},
+			wantOut: `secret "staging/rails" copied to "production:app/rails"
+`,
+		},
+
+		"overwrite existing secret": {
+			args: []string{"staging/rails", "production:app/rails"},
+			opts: copyOpts{
+				keys:       []string{"rails-env"},
+				keepLabels: []string{"app"},
+				overwrite:  true,
+			},
+			production: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "rails",
+					ResourceVersion: "67890",
+					Labels: map[string]string{
+						"env": "production",
+					},
+				},
+				Type: "example.com/custom",
+				Data: map[string][]byte{
+					"foo": []byte("bar"),
+				},
+			},
+			wantUpdated: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "rails",
+					ResourceVersion: "67890",
+					Labels: map[string]string{
+						"app": "rails",
+						"env": "production",
+					},
+				},
+				Type: "example.com/custom",
+				Data: map[string][]byte{
+					"rails-env": []byte("staging"),
+				},
+			},
+			wantOut: `secret "staging/rails" copied to "production:app/rails"
+`,
+		},
+
+		"existing secret without --overwrite": {
+			args: []string{"staging/rails", "production:app/rails"},
+			production: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Type: "example.com/custom",
+			},
+			wantErr: errors.New(`secret "production:app/rails" already exists, use --overwrite to replace it`),
+		},
+
+		"existing secret with different type": {
+			args: []string{"staging/rails", "production:app/rails"},
+			opts: copyOpts{
+				overwrite: true,
+			},
+			production: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantErr: errors.New(`cannot overwrite secret "production:app/rails" of type "Opaque" with type "example.com/custom"`),
+		},
+
+		"missing key": {
+			args: []string{"staging/rails", "production:app/rails"},
+			opts: copyOpts{
+				keys: []string{"foo"},
+			},
+			wantErr: errors.New(`secret "staging/rails": the key foo does not exist`),
+		},
+
+		"same secret": {
+			args:    []string{"staging/rails", "staging/rails"},
+			wantErr: errors.New(`source and destination are the same secret "staging/rails"`),
+		},
+
+		"same secret in current context": {
+			args:    []string{"staging/rails", "minikube:staging/rails"},
+			wantErr: errors.New(`source and destination are the same secret "staging/rails"`),
+		},
+
+		"unknown context": {
+			args:    []string{"staging/rails", "unknown:app/rails"},
+			wantErr: errors.New(`context "unknown" does not exist`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			production := &fakeClient{
+				contextName:          "production",
+				getSecretResponse:    tc.production,
+				getSecretErr:         tc.productionGetErr,
+				updateSecretResponse: tc.production,
+			}
+
+			newClient := newFakeClientFactory(map[string]*fakeClient{
+				"": {
+					contextName:       "minikube",
+					getSecretResponse: secret,
+				},
+				"minikube": {
+					contextName:       "minikube",
+					getSecretResponse: secret,
+				},
+				"production": production,
+			})
+
+			var out bytes.Buffer
+
+			err := runCopy(context.Background(), newClient, namespace, tc.args, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				if production.createdSecret != nil || production.updatedSecret != nil {
+					t.Fatalf("want no secret written")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(production.createdSecret, tc.wantCreated) {
+					t.Fatalf("want created %#v, got %#v", tc.wantCreated, production.createdSecret)
+				}
+
+				if !reflect.DeepEqual(production.updatedSecret, tc.wantUpdated) {
+					t.Fatalf("want updated %#v, got %#v", tc.wantUpdated, production.updatedSecret)
+				}
+
+				if out.String() != tc.wantOut {
+					t.Logf("want:\n%s", tc.wantOut)
+					t.Logf("got:\n%s", out.String())
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+		})
+	}
+}
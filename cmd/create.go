@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+func newCreateCmd(in io.Reader, out io.Writer) *cobra.Command {
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create secret of specific type",
+		Long: `Create secret of specific type
+
+The keys required by the type are set, and their values are validated before the secret is created.
+`,
+	}
+
+	createCmd.AddCommand(newCreateBasicAuthCmd(in, out))
+	createCmd.AddCommand(newCreateDockerRegistryCmd(in, out))
+	createCmd.AddCommand(newCreateSSHAuthCmd(out))
+	createCmd.AddCommand(newCreateTLSCmd(out))
+
+	return createCmd
+}
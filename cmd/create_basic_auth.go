@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type createBasicAuthOpts struct {
+	credential registryCredentialOpts
+}
+
+func newCreateBasicAuthCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := createBasicAuthOpts{
+		credential: newRegistryCredentialOpts(in),
+	}
+
+	createBasicAuthCmd := &cobra.Command{
+		Use:   "basic-auth NAME --username USERNAME [--password-stdin]",
+		Short: "Create basic-auth secret from username and password",
+		Long: `Create basic-auth secret from username and password
+
+The secret of type kubernetes.io/basic-auth is created with keys username and password. The password is read from
+stdin with --password-stdin, otherwise from interactive prompt:
+
+$ k8sec create basic-auth postgres --username dtan4
+Password:
+postgres
+
+Both username and password are required in basic-auth secrets, though the API server accepts either of them. Commands
+such as "k8sec set" and "k8sec unset" do not update basic-auth secrets to lack either of them.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("one secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runCreateBasicAuth(ctx, k8sclient, namespace, args, in, out, &opts)
+		},
+	}
+
+	addCredentialFlags(createBasicAuthCmd.Flags(), &opts.credential)
+
+	return createBasicAuthCmd
+}
+
+func runCreateBasicAuth(ctx context.Context, k8sclient client.Client, namespace string, args []string, in io.Reader, out io.Writer, opts *createBasicAuthOpts) error {
+	name := args[0]
+
+	username, password, err := readCredential(in, &opts.credential)
+	if err != nil {
+		return err
+	}
+
+	s := &v1.Secret{
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			v1.BasicAuthUsernameKey: []byte(username),
+			v1.BasicAuthPasswordKey: password,
+		},
+	}
+	s.SetName(name)
+
+	if err := secrettype.Validate(s, time.Now()); err != nil {
+		return err
+	}
+
+	if _, err := k8sclient.CreateSecret(ctx, namespace, s); err != nil {
+		return fmt.Errorf("create secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestRunCreateBasicAuth(t *testing.T) {
+	testcases := map[string]struct {
+		opts     createBasicAuthOpts
+		input    string
+		wantData map[string][]byte
+		wantOut  string
+		wantErr  error
+	}{
+		"password from stdin": {
+			opts: createBasicAuthOpts{
+				credential: registryCredentialOpts{
+					username:      "dtan4",
+					passwordStdin: true,
+				},
+			},
+			input: "p@ssw0rd\n",
+			wantData: map[string][]byte{
+				"username": []byte("dtan4"),
+				"password": []byte("p@ssw0rd"),
+			},
+			wantOut: "postgres\n",
+		},
+		"password from prompt": {
+			opts: createBasicAuthOpts{
+				credential: registryCredentialOpts{
+					username: "dtan4",
+					readPassword: func(prompt string) ([]byte, error) {
+						return []byte("p@ssw0rd"), nil
+					},
+				},
+			},
+			wantData: map[string][]byte{
+				"username": []byte("dtan4"),
+				"password": []byte("p@ssw0rd"),
+			},
+			wantOut: "postgres\n",
+		},
+		"no username": {
+			opts: createBasicAuthOpts{
+				credential: registryCredentialOpts{
+					passwordStdin: true,
+				},
+			},
+			input:   "p@ssw0rd\n",
+			wantErr: errors.New("--username must be specified"),
+		},
+		"prompt error": {
+			opts: createBasicAuthOpts{
+				credential: registryCredentialOpts{
+					username: "dtan4",
+					readPassword: func(prompt string) ([]byte, error) {
+						return nil, errors.New("stdin is not a terminal")
+					},
+				},
+			},
+			wantErr: errors.New("read password from prompt: stdin is not a terminal"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{}
+
+			var out bytes.Buffer
+
+			err := runCreateBasicAuth(context.Background(), k8sclient, namespace, []string{"postgres"}, strings.NewReader(tc.input), &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if k8sclient.createdSecret.Type != v1.SecretTypeBasicAuth {
+				t.Errorf("want type %q, got %q", v1.SecretTypeBasicAuth, k8sclient.createdSecret.Type)
+			}
+
+			if !reflect.DeepEqual(k8sclient.createdSecret.Data, tc.wantData) {
+				t.Errorf("want data %q, got %q", tc.wantData, k8sclient.createdSecret.Data)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type createDockerRegistryOpts struct {
+	server     string
+	credential registryCredentialOpts
+}
+
+func newCreateDockerRegistryCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := createDockerRegistryOpts{
+		credential: newRegistryCredentialOpts(in),
+	}
+
+	createDockerRegistryCmd := &cobra.Command{
+		Use:   "docker-registry NAME [--server SERVER] --username USERNAME [--password-stdin] [--email EMAIL]",
+		Short: "Create docker-registry secret used as imagePullSecrets",
+		Long: `Create docker-registry secret used as imagePullSecrets
+
+The secret of type kubernetes.io/dockerconfigjson is created with key .dockerconfigjson. The password is read from
+stdin with --password-stdin, otherwise from interactive prompt, so that it does not appear in shell history and
+process listings:
+
+$ echo "$GITHUB_TOKEN" | k8sec create docker-registry regcred --server ghcr.io --username dtan4 --password-stdin
+regcred
+
+Docker Hub is used if --server is not specified. Use "k8sec registry add" to add other registries to the secret.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("one secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runCreateDockerRegistry(ctx, k8sclient, namespace, args, in, out, &opts)
+		},
+	}
+
+	createDockerRegistryCmd.Flags().StringVar(&opts.server, "server", secrettype.DefaultDockerRegistryServer, "Server of the registry")
+	addRegistryCredentialFlags(createDockerRegistryCmd.Flags(), &opts.credential)
+
+	return createDockerRegistryCmd
+}
+
+func runCreateDockerRegistry(ctx context.Context, k8sclient client.Client, namespace string, args []string, in io.Reader, out io.Writer, opts *createDockerRegistryOpts) error {
+	name := args[0]
+
+	if opts.server == "" {
+		return errors.New("--server must not be empty")
+	}
+
+	entry, err := readRegistryEntry(in, &opts.credential)
+	if err != nil {
+		return err
+	}
+
+	c := secrettype.NewDockerConfig()
+
+	if err := c.Set(opts.server, entry); err != nil {
+		return err
+	}
+
+	s := &v1.Secret{
+		Type: v1.SecretTypeDockerConfigJson,
+	}
+	s.SetName(name)
+
+	if err := setDockerConfig(s, c); err != nil {
+		return err
+	}
+
+	if err := secrettype.Validate(s, time.Now()); err != nil {
+		return err
+	}
+
+	if _, err := k8sclient.CreateSecret(ctx, namespace, s); err != nil {
+		return fmt.Errorf("create secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestRunCreateDockerRegistry(t *testing.T) {
+	testcases := map[string]struct {
+		opts     createDockerRegistryOpts
+		input    string
+		err      error
+		wantData map[string][]byte
+		wantOut  string
+		wantErr  error
+	}{
+		"password from stdin": {
+			opts: createDockerRegistryOpts{
+				server: "ghcr.io",
+				credential: registryCredentialOpts{
+					username:      "dtan4",
+					passwordStdin: true,
+				},
+			},
+			input: "p@ss\n",
+			wantData: map[string][]byte{
+				".dockerconfigjson": []byte(`{"auths":{"ghcr.io":{"username":"dtan4","password":"p@ss","auth":"ZHRhbjQ6cEBzcw=="}}}`),
+			},
+			wantOut: "regcred\n",
+		},
+		"password from prompt": {
+			opts: createDockerRegistryOpts{
+				server: "https://index.docker.io/v1/",
+				credential: registryCredentialOpts{
+					username: "dtan4",
+					email:    "dtan4@example.com",
+					readPassword: func(prompt string) ([]byte, error) {
+						return []byte("p@ss"), nil
+					},
+				},
+			},
+			wantData: map[string][]byte{
+				".dockerconfigjson": []byte(`{"auths":{"https://index.docker.io/v1/":{"username":"dtan4","password":"p@ss","email":"dtan4@example.com","auth":"ZHRhbjQ6cEBzcw=="}}}`),
+			},
+			wantOut: "regcred\n",
+		},
+		"no username": {
+			opts: createDockerRegistryOpts{
+				server: "ghcr.io",
+				credential: registryCredentialOpts{
+					passwordStdin: true,
+				},
+			},
+			input:   "p@ss\n",
+			wantErr: errors.New("--username must be specified"),
+		},
+		"empty password": {
+			opts: createDockerRegistryOpts{
+				server: "ghcr.io",
+				credential: registryCredentialOpts{
+					username:      "dtan4",
+					passwordStdin: true,
+				},
+			},
+			input:   "\n",
+			wantErr: errors.New("password must not be empty"),
+		},
+		"create error": {
+			opts: createDockerRegistryOpts{
+				server: "ghcr.io",
+				credential: registryCredentialOpts{
+					username:      "dtan4",
+					passwordStdin: true,
+				},
+			},
+			input:   "p@ss",
+			err:     errors.New(`secrets "regcred" already exists`),
+			wantErr: errors.New(`create secret "regcred": secrets "regcred" already exists`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				err: tc.err,
+			}
+
+			var out bytes.Buffer
+
+			err := runCreateDockerRegistry(context.Background(), k8sclient, namespace, []string{"regcred"}, strings.NewReader(tc.input), &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if k8sclient.createdSecret.Type != v1.SecretTypeDockerConfigJson {
+				t.Errorf("want type %q, got %q", v1.SecretTypeDockerConfigJson, k8sclient.createdSecret.Type)
+			}
+
+			if !reflect.DeepEqual(k8sclient.createdSecret.Data, tc.wantData) {
+				t.Errorf("want data %q, got %q", tc.wantData, k8sclient.createdSecret.Data)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
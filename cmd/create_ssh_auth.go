@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type createSSHAuthOpts struct {
+	keyFile string
+}
+
+func newCreateSSHAuthCmd(out io.Writer) *cobra.Command {
+	opts := createSSHAuthOpts{}
+
+	createSSHAuthCmd := &cobra.Command{
+		Use:   "ssh-auth NAME --key FILE",
+		Short: "Create ssh-auth secret from SSH private key",
+		Long: `Create ssh-auth secret from SSH private key
+
+The secret of type kubernetes.io/ssh-auth is created with key ssh-privatekey:
+
+$ k8sec create ssh-auth deploy-key --key ~/.ssh/id_ed25519
+deploy-key
+
+The private key must be in OpenSSH or PEM format. Keys encrypted with passphrase are stored without decryption.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("one secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runCreateSSHAuth(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	createSSHAuthCmd.Flags().StringVar(&opts.keyFile, "key", "", "SSH private key file")
+	createSSHAuthCmd.MarkFlagRequired("key")
+
+	return createSSHAuthCmd
+}
+
+func runCreateSSHAuth(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *createSSHAuthOpts) error {
+	name := args[0]
+
+	key, err := os.ReadFile(opts.keyFile)
+	if err != nil {
+		return fmt.Errorf("read private key: %w", err)
+	}
+
+	s := &v1.Secret{
+		Type: v1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			v1.SSHAuthPrivateKey: key,
+		},
+	}
+	s.SetName(name)
+
+	if err := secrettype.Validate(s, time.Now()); err != nil {
+		return err
+	}
+
+	if _, err := k8sclient.CreateSecret(ctx, namespace, s); err != nil {
+		return fmt.Errorf("create secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
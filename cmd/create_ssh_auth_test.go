@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestRunCreateSSHAuth(t *testing.T) {
+	dir := t.TempDir()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey := pem.EncodeToMemory(block)
+
+	keyFile := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyFile, privateKey, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyFile := filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(publicKeyFile, []byte("ssh-ed25519 AAAA dtan4@example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := map[string]struct {
+		keyFile  string
+		wantData map[string][]byte
+		wantOut  string
+		wantErr  error
+	}{
+		"create": {
+			keyFile: keyFile,
+			wantData: map[string][]byte{
+				"ssh-privatekey": privateKey,
+			},
+			wantOut: "deploy-key\n",
+		},
+		"public key": {
+			keyFile: publicKeyFile,
+			wantErr: errors.New("invalid SSH private key: ssh: no key found"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{}
+
+			var out bytes.Buffer
+
+			err := runCreateSSHAuth(context.Background(), k8sclient, namespace, []string{"deploy-key"}, &out, &createSSHAuthOpts{keyFile: tc.keyFile})
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if k8sclient.createdSecret.Type != v1.SecretTypeSSHAuth {
+				t.Errorf("want type %q, got %q", v1.SecretTypeSSHAuth, k8sclient.createdSecret.Type)
+			}
+
+			if !reflect.DeepEqual(k8sclient.createdSecret.Data, tc.wantData) {
+				t.Errorf("want data %q, got %q", tc.wantData, k8sclient.createdSecret.Data)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
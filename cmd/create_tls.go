@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type createTLSOpts struct {
+	certFile string
+	keyFile  string
+}
+
+func newCreateTLSCmd(out io.Writer) *cobra.Command {
+	opts := createTLSOpts{}
+
+	createTLSCmd := &cobra.Command{
+		Use:   "tls NAME --cert FILE --key FILE",
+		Short: "Create TLS secret from certificate and private key",
+		Long: `Create TLS secret from certificate and private key
+
+The secret of type kubernetes.io/tls is created with keys tls.crt and tls.key:
+
+$ k8sec create tls nginx --cert fullchain.pem --key privkey.pem
+nginx
+
+The certificate file must be PEM-encoded, and may contain intermediate certificates after the server certificate.
+The secret is not created if the private key does not match the certificate, the certificates are not ordered from
+leaf to root, or any of them has expired.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("one secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runCreateTLS(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	createTLSCmd.Flags().StringVar(&opts.certFile, "cert", "", "PEM-encoded certificate file")
+	createTLSCmd.Flags().StringVar(&opts.keyFile, "key", "", "PEM-encoded private key file")
+	createTLSCmd.MarkFlagRequired("cert")
+	createTLSCmd.MarkFlagRequired("key")
+
+	return createTLSCmd
+}
+
+func runCreateTLS(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *createTLSOpts) error {
+	name := args[0]
+
+	cert, err := os.ReadFile(opts.certFile)
+	if err != nil {
+		return fmt.Errorf("read certificate: %w", err)
+	}
+
+	key, err := os.ReadFile(opts.keyFile)
+	if err != nil {
+		return fmt.Errorf("read private key: %w", err)
+	}
+
+	s := &v1.Secret{
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       cert,
+			v1.TLSPrivateKeyKey: key,
+		},
+	}
+	s.SetName(name)
+
+	if err := secrettype.Validate(s, time.Now()); err != nil {
+		return err
+	}
+
+	if _, err := k8sclient.CreateSecret(ctx, namespace, s); err != nil {
+		return fmt.Errorf("create secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
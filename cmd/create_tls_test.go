@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// newTestCertificate returns PEM-encoded self-signed certificate and its private key
+func newTestCertificate(t *testing.T, cn string, notAfter time.Time, dnsNames ...string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+		DNSNames:     dnsNames,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestRunCreateTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name string, b []byte) string {
+		path := filepath.Join(dir, name)
+
+		if err := os.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	cert, key := newTestCertificate(t, "example.com", time.Now().AddDate(1, 0, 0), "example.com")
+	_, otherKey := newTestCertificate(t, "example.org", time.Now().AddDate(1, 0, 0), "example.org")
+
+	certFile := writeFile("tls.crt", cert)
+	keyFile := writeFile("tls.key", key)
+	otherKeyFile := writeFile("other.key", otherKey)
+
+	testcases := map[string]struct {
+		opts     createTLSOpts
+		err      error
+		wantData map[string][]byte
+		wantOut  string
+		wantErr  error
+	}{
+		"create": {
+			opts: createTLSOpts{
+				certFile: certFile,
+				keyFile:  keyFile,
+			},
+			wantData: map[string][]byte{
+				"tls.crt": cert,
+				"tls.key": key,
+			},
+			wantOut: "nginx\n",
+		},
+		"key mismatch": {
+			opts: createTLSOpts{
+				certFile: certFile,
+				keyFile:  otherKeyFile,
+			},
+			wantErr: errors.New(`private key does not match certificate "CN=example.com"`),
+		},
+		"certificate does not exist": {
+			opts: createTLSOpts{
+				certFile: filepath.Join(dir, "nonexistent.crt"),
+				keyFile:  keyFile,
+			},
+			wantErr: errors.New("read certificate: open " + filepath.Join(dir, "nonexistent.crt") + ": no such file or directory"),
+		},
+		"create error": {
+			opts: createTLSOpts{
+				certFile: certFile,
+				keyFile:  keyFile,
+			},
+			err:     errors.New(`secrets "nginx" already exists`),
+			wantErr: errors.New(`create secret "nginx": secrets "nginx" already exists`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				err: tc.err,
+			}
+
+			var out bytes.Buffer
+
+			err := runCreateTLS(context.Background(), k8sclient, namespace, []string{"nginx"}, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if k8sclient.createdSecret.Type != v1.SecretTypeTLS {
+				t.Errorf("want type %q, got %q", v1.SecretTypeTLS, k8sclient.createdSecret.Type)
+			}
+
+			if !reflect.DeepEqual(k8sclient.createdSecret.Data, tc.wantData) {
+				t.Errorf("want data %#v, got %#v", tc.wantData, k8sclient.createdSecret.Data)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type deleteOpts struct {
+	yes         bool
+	selector    string
+	cascade     string
+	gracePeriod int64
+}
+
+func newDeleteCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := deleteOpts{}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [NAME ...]",
+		Short: "Delete secrets",
+		Long: `Delete secrets
+
+Secrets to be deleted are listed and confirmed before deletion:
+
+$ k8sec delete rails
+The following secrets in namespace "default" will be deleted:
+  rails
+Are you sure? [y/N]: y
+secret "rails" deleted
+
+Delete all secrets matching the label selector without confirmation:
+
+$ k8sec delete -l app=rails --yes
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			// Nothing is deleted actually in dry run
+			if rootOpts.dryRun != dryRunModeNone {
+				opts.yes = true
+			}
+
+			return runDelete(ctx, k8sclient, namespace, args, in, out, &opts)
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Delete without confirmation")
+	deleteCmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Label selector to filter secrets to be deleted (e.g. app=rails,tier!=frontend)")
+	deleteCmd.Flags().StringVar(&opts.cascade, "cascade", "", `Propagation policy of dependents. One of: "background", "foreground" or "orphan". API server default is used if empty`)
+	deleteCmd.Flags().Int64Var(&opts.gracePeriod, "grace-period", -1, "Seconds given to the secret before deletion. API server default is used if negative")
+
+	return deleteCmd
+}
+
+func runDelete(ctx context.Context, k8sclient client.Client, namespace string, args []string, in io.Reader, out io.Writer, opts *deleteOpts) error {
+	if len(args) == 0 && opts.selector == "" {
+		return errors.New("secret names or label selector must be specified")
+	}
+
+	if len(args) > 0 && opts.selector != "" {
+		return errors.New("secret names cannot be specified with label selector")
+	}
+
+	deleteOptions, err := newDeleteOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	names := args
+
+	if opts.selector != "" {
+		names, err = selectSecrets(ctx, k8sclient, namespace, opts.selector)
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			fmt.Fprintf(out, "No secrets in namespace %q match %q\n", namespace, opts.selector)
+			return nil
+		}
+	}
+
+	if !opts.yes {
+		fmt.Fprintf(out, "The following secrets in namespace %q will be deleted:\n", namespace)
+
+		for _, name := range names {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+
+		ok, err := confirm(in, out, "Are you sure?")
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errors.New("deletion cancelled")
+		}
+	}
+
+	for _, name := range names {
+		if err := k8sclient.DeleteSecret(ctx, namespace, name, deleteOptions); err != nil {
+			return fmt.Errorf("delete secret %q: %w", name, err)
+		}
+
+		if !isDryRun(k8sclient) {
+			fmt.Fprintf(out, "secret %q deleted\n", name)
+		}
+	}
+
+	return nil
+}
+
+// newDeleteOptions converts command line options to client.DeleteOptions
+func newDeleteOptions(opts *deleteOpts) (client.DeleteOptions, error) {
+	deleteOptions := client.DeleteOptions{}
+
+	switch opts.cascade {
+	case "":
+	case "background":
+		policy := metav1.DeletePropagationBackground
+		deleteOptions.PropagationPolicy = &policy
+	case "foreground":
+		policy := metav1.DeletePropagationForeground
+		deleteOptions.PropagationPolicy = &policy
+	case "orphan":
+		policy := metav1.DeletePropagationOrphan
+		deleteOptions.PropagationPolicy = &policy
+	default:
+		return client.DeleteOptions{}, fmt.Errorf("unknown cascade %q, must be one of background, foreground or orphan", opts.cascade)
+	}
+
+	if opts.gracePeriod >= 0 {
+		gracePeriod := opts.gracePeriod
+		deleteOptions.GracePeriodSeconds = &gracePeriod
+	}
+
+	return deleteOptions, nil
+}
+
+// selectSecrets returns the sorted names of secrets matching the given label selector
+func selectSecrets(ctx context.Context, k8sclient client.Client, namespace, selector string) ([]string, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parse label selector %q: %w", selector, err)
+	}
+
+	secrets, err := k8sclient.ListSecrets(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list secrets: %w", err)
+	}
+
+	names := []string{}
+
+	for _, secret := range secrets.Items {
+		if sel.Matches(labels.Set(secret.Labels)) {
+			names = append(names, secret.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// confirm asks the question and reports whether the answer read from in is yes
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("read answer: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunDelete(t *testing.T) {
+	orphan := metav1.DeletePropagationOrphan
+	gracePeriod := int64(0)
+
+	secrets := &v1.SecretList{
+		Items: []v1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "rails",
+					Labels: map[string]string{"app": "rails", "tier": "backend"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "nginx",
+					Labels: map[string]string{"app": "nginx", "tier": "frontend"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "sidekiq",
+					Labels: map[string]string{"app": "rails", "tier": "worker"},
+				},
+			},
+		},
+	}
+
+	testcases := map[string]struct {
+		args             []string
+		opts             deleteOpts
+		dryRun           bool
+		input            string
+		deleteSecretErrs map[string]error
+		wantDeleted      []string
+		wantOptions      client.DeleteOptions
+		wantOut          string
+		wantErr          error
+	}{
+		"confirmed": {
+			args: []string{"rails", "nginx"},
+			opts: deleteOpts{
+				gracePeriod: -1,
+			},
+			input:       "y\n",
+			wantDeleted: []string{"rails", "nginx"},
+			wantOut: `The following secrets in namespace "test" will be deleted:
+  rails
+  nginx
+Are you sure? [y/N]: secret "rails" deleted
+secret "nginx" deleted
+`,
+		},
+
+		"cancelled": {
+			args:    []string{"rails"},
+			input:   "n\n",
+			wantErr: errors.New("deletion cancelled"),
+		},
+
+		"no answer": {
+			args:    []string{"rails"},
+			input:   "",
+			wantErr: errors.New("deletion cancelled"),
+		},
+
+		"without confirmation": {
+			args: []string{"rails"},
+			opts: deleteOpts{
+				yes:         true,
+				cascade:     "orphan",
+				gracePeriod: 0,
+			},
+			wantDeleted: []string{"rails"},
+			wantOptions: client.DeleteOptions{
+				PropagationPolicy:  &orphan,
+				GracePeriodSeconds: &gracePeriod,
+			},
+			wantOut: `secret "rails" deleted
+`,
+		},
+
+		"dry run": {
+			args: []string{"rails"},
+			opts: deleteOpts{
+				yes:         true,
+				gracePeriod: -1,
+			},
+			dryRun: true,
+			wantOut: `secret "rails" in namespace "test" would be deleted (client dry run)
+`,
+		},
+
+		"label selector": {
+			opts: deleteOpts{
+				yes:         true,
+				selector:    "app=rails,tier!=worker",
+				gracePeriod: -1,
+			},
+			wantDeleted: []string{"rails"},
+			wantOut: `secret "rails" deleted
+`,
+		},
+
+		"label selector matching nothing": {
+			opts: deleteOpts{
+				selector:    "app=unknown",
+				gracePeriod: -1,
+			},
+			wantOut: `No secrets in namespace "test" match "app=unknown"
+`,
+		},
+
+		"no target": {
+			wantErr: errors.New("secret names or label selector must be specified"),
+		},
+
+		"both names and selector": {
+			args: []string{"rails"},
+			opts: deleteOpts{
+				selector: "app=rails",
+			},
+			wantErr: errors.New("secret names cannot be specified with label selector"),
+		},
+
+		"invalid cascade": {
+			args: []string{"rails"},
+			opts: deleteOpts{
+				cascade: "cascade",
+			},
+			wantErr: errors.New(`unknown cascade "cascade", must be one of background, foreground or orphan`),
+		},
+
+		"error at delete secret": {
+			args: []string{"rails"},
+			opts: deleteOpts{
+				yes:         true,
+				gracePeriod: -1,
+			},
+			deleteSecretErrs: map[string]error{
+				"rails": errors.New("cannot delete secret"),
+			},
+			wantErr: errors.New(`delete secret "rails": cannot delete secret`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				listSecretsResponse: secrets,
+				deleteSecretErrs:    tc.deleteSecretErrs,
+			}
+
+			var (
+				out bytes.Buffer
+				c   client.Client = k8sclient
+			)
+
+			if tc.dryRun {
+				c = &dryRunClient{
+					Client: k8sclient,
+					out:    &out,
+				}
+			}
+
+			err := runDelete(context.Background(), c, namespace, tc.args, strings.NewReader(tc.input), &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				if len(k8sclient.deletedSecrets) > 0 {
+					t.Fatalf("want no secret deleted, got %v", k8sclient.deletedSecrets)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(k8sclient.deletedSecrets, tc.wantDeleted) {
+					t.Fatalf("want deleted %v, got %v", tc.wantDeleted, k8sclient.deletedSecrets)
+				}
+
+				if !reflect.DeepEqual(k8sclient.deleteOptions, tc.wantOptions) {
+					t.Fatalf("want delete options %#v, got %#v", tc.wantOptions, k8sclient.deleteOptions)
+				}
+
+				if out.String() != tc.wantOut {
+					t.Logf("want:\n%s", tc.wantOut)
+					t.Logf("got:\n%s", out.String())
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+		})
+	}
+}
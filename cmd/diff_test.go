@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunDiff(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rails",
+		},
+		Data: map[string][]byte{
+			"database-url": []byte("postgres://example.com:5432/dbname"),
+			"rails-env":    []byte("production"),
+			"foo":          []byte("bar"),
+		},
+	}
+
+	testcases := map[string]struct {
+		args         []string
+		opts         diffOpts
+		secret       *v1.Secret
+		production   *v1.Secret
+		input        string
+		getSecretErr error
+		wantOut      string
+		wantErr      error
+	}{
+		"no difference": {
+			args:   []string{"rails"},
+			secret: secret,
+			input: `database-url="postgres://example.com:5432/dbname"
+rails-env=production
+foo=bar
+`,
+			wantOut: "",
+			wantErr: nil,
+		},
+
+		"differences with masked values": {
+			args:   []string{"rails"},
+			secret: secret,
+			input: `database-url="postgres://example.com:5432/newdb"
+foo=bar
+secret-key-base=abcdef
+`,
+			wantOut: `+ secret-key-base=********
+~ database-url=******** => ********
+- rails-env=********
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"differences with values": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				showValues: true,
+			},
+			secret: secret,
+			input: `database-url="postgres://${foo}@example.com:5432/dbname"
+rails-env=production
+foo=bar
+`,
+			wantOut: `~ database-url="postgres://example.com:5432/dbname" => "postgres://bar@example.com:5432/dbname"
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"JSON": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				input: inputOpts{
+					format: loadFormatJSON,
+				},
+			},
+			secret: secret,
+			input:  `{"database-url": "postgres://example.com:5432/dbname", "rails-env": "staging", "foo": "bar"}`,
+			wantOut: `~ rails-env=******** => ********
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"YAML with flatten": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				input: inputOpts{
+					format:    loadFormatYAML,
+					flatten:   true,
+					separator: "-",
+				},
+				showValues: true,
+			},
+			secret: secret,
+			input: `database:
+  url: postgres://example.com:5432/newdb
+rails-env: production
+foo: bar
+`,
+			wantOut: `~ database-url="postgres://example.com:5432/dbname" => "postgres://example.com:5432/newdb"
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"manifest": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				input: inputOpts{
+					format: loadFormatManifest,
+				},
+				showValues: true,
+			},
+			secret: secret,
+			input: `apiVersion: v1
+kind: Secret
+metadata:
+  name: rails-staging
+data:
+  database-url: cG9zdGdyZXM6Ly9leGFtcGxlLmNvbTo1NDMyL2RibmFtZQ==
+stringData:
+  rails-env: staging
+`,
+			wantOut: `~ rails-env="production" => "staging"
+- foo="bar"
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"multiple manifests": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				input: inputOpts{
+					format: loadFormatManifest,
+				},
+			},
+			secret: secret,
+			input: `apiVersion: v1
+kind: Secret
+metadata:
+  name: rails
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: sidekiq
+`,
+			wantErr: errors.New("<stdin>: only one Secret manifest can be compared, got 2"),
+		},
+
+		"unknown format": {
+			args: []string{"rails"},
+			opts: diffOpts{
+				input: inputOpts{
+					format: "toml",
+				},
+			},
+			secret:  secret,
+			wantErr: errors.New(`--format must be one of "dotenv", "json", "yaml" or "manifest", got "toml"`),
+		},
+
+		"secret does not exist": {
+			args:         []string{"rails"},
+			getSecretErr: apierrors.NewNotFound(v1.Resource("secrets"), "rails"),
+			input:        `rails-env=production`,
+			wantOut: `+ rails-env=********
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"error at get secret": {
+			args:         []string{"rails"},
+			getSecretErr: errors.New("cannot get secret"),
+			wantErr:      errors.New(`get secret "rails": cannot get secret`),
+		},
+
+		"two secrets": {
+			args:   []string{"staging/rails", "production:rails"},
+			secret: secret,
+			production: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"database-url": []byte("postgres://example.com:5432/production"),
+					"rails-env":    []byte("production"),
+					"bar":          []byte("baz"),
+				},
+			},
+			wantOut: `+ bar=********
+~ database-url=******** => ********
+- foo=********
+`,
+			wantErr: &exitError{code: 1},
+		},
+
+		"two secrets without difference": {
+			args:       []string{"staging/rails", "production:app/rails"},
+			secret:     secret,
+			production: secret,
+			wantOut:    "",
+			wantErr:    nil,
+		},
+
+		"two secrets with --filename": {
+			args: []string{"staging/rails", "production/rails"},
+			opts: diffOpts{
+				filename: ".env",
+			},
+			wantErr: errors.New("--filename cannot be used with two secrets"),
+		},
+
+		"unknown context": {
+			args:    []string{"staging/rails", "unknown:rails"},
+			secret:  secret,
+			wantErr: errors.New(`context "unknown" does not exist`),
+		},
+
+		"invalid reference": {
+			args:    []string{"staging/"},
+			wantErr: errors.New(`invalid secret reference "staging/": name is empty`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			newClient := newFakeClientFactory(map[string]*fakeClient{
+				"": {
+					getSecretResponse: tc.secret,
+					getSecretErr:      tc.getSecretErr,
+				},
+				"production": {
+					getSecretResponse: tc.production,
+				},
+			})
+
+			in := strings.NewReader(tc.input)
+			var out bytes.Buffer
+
+			err := runDiff(context.Background(), newClient, namespace, tc.args, in, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+
+			if out.String() != tc.wantOut {
+				t.Logf("want:\n%s", tc.wantOut)
+				t.Logf("got:\n%s", out.String())
+				t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	dryRunModeNone   = "none"
+	dryRunModeClient = "client"
+	dryRunModeServer = "server"
+)
+
+// dryRunClient wraps client.Client and prints the secrets to be written, with values masked.
+// In client mode, modifying requests are not sent to API server at all.
+// In server mode, they are sent as server-side dry run requests and the secrets returned from API server are printed.
+type dryRunClient struct {
+	client.Client
+
+	out    io.Writer
+	server bool
+}
+
+// isDryRun reports whether k8sclient only prints the secrets to be written.
+// Commands skip their own success messages in dry run, since dryRunClient prints what would be done.
+func isDryRun(k8sclient client.Client) bool {
+	_, ok := k8sclient.(*dryRunClient)
+	return ok
+}
+
+func (c *dryRunClient) CreateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
+	if c.server {
+		s, err := c.Client.CreateSecret(ctx, namespace, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		secret = s
+	}
+
+	return secret, c.print("created", namespace, secret)
+}
+
+func (c *dryRunClient) UpdateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
+	if c.server {
+		s, err := c.Client.UpdateSecret(ctx, namespace, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		secret = s
+	}
+
+	return secret, c.print("updated", namespace, secret)
+}
+
+func (c *dryRunClient) DeleteSecret(ctx context.Context, namespace, name string, opts client.DeleteOptions) error {
+	if c.server {
+		if err := c.Client.DeleteSecret(ctx, namespace, name, opts); err != nil {
+			return err
+		}
+	}
+
+	c.printMessage("deleted", namespace, name)
+
+	return nil
+}
+
+func (c *dryRunClient) print(action, namespace string, secret *v1.Secret) error {
+	c.printMessage(action, namespace, secret.Name)
+
+	mask := func(_ []byte) string {
+		return maskedValue
+	}
+
+	return printSecretsTable(c.out, newSecretRecords(secret, mask), false)
+}
+
+func (c *dryRunClient) printMessage(action, namespace, name string) {
+	mode := dryRunModeClient
+	if c.server {
+		mode = dryRunModeServer
+	}
+
+	fmt.Fprintf(c.out, "secret %q in namespace %q would be %s (%s dry run)\n", name, namespace, action, mode)
+}
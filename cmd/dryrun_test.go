@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunClient(t *testing.T) {
+	testcases := map[string]struct {
+		server      bool
+		wantOut     string
+		wantUpdated bool
+	}{
+		"client": {
+			server: false,
+			wantOut: `secret "rails" in namespace "test" would be updated (client dry run)
+NAME	TYPE	KEY		VALUE
+rails	Opaque	database-url	********
+rails	Opaque	rails-env	********
+`,
+			wantUpdated: false,
+		},
+
+		"server": {
+			server: true,
+			wantOut: `secret "rails" in namespace "test" would be updated (server dry run)
+NAME	TYPE	KEY		VALUE
+rails	Opaque	database-url	********
+rails	Opaque	rails-env	********
+`,
+			wantUpdated: true,
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Type: v1.SecretTypeOpaque,
+				Data: map[string][]byte{
+					"database-url": []byte("postgres://example.com:5432/dbname"),
+				},
+			}
+
+			fake := &fakeClient{
+				getSecretResponse: secret,
+				listSecretsResponse: &v1.SecretList{
+					Items: []v1.Secret{*secret},
+				},
+				updateSecretResponse: secret,
+			}
+
+			var out bytes.Buffer
+
+			k8sclient := &dryRunClient{
+				Client: fake,
+				out:    &out,
+				server: tc.server,
+			}
+
+			err := runSet(context.Background(), k8sclient, namespace, []string{"rails", "rails-env=production"}, nil, &out, &setOpts{})
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if out.String() != tc.wantOut {
+				t.Logf("want:\n%s", tc.wantOut)
+				t.Logf("got:\n%s", out.String())
+				t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+			}
+
+			if got := fake.updatedSecret != nil; got != tc.wantUpdated {
+				t.Fatalf("want secret updated %t, got %t", tc.wantUpdated, got)
+			}
+		})
+	}
+}
@@ -1,22 +1,38 @@
 package cmd
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sort"
-	"strconv"
 
 	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/dotenv"
+	"github.com/dtan4/k8sec/pkg/envfile"
+	"github.com/dtan4/k8sec/pkg/keyvalue"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Formats of dumped secrets
+const (
+	dumpFormatDotenv   = "dotenv"
+	dumpFormatJSON     = "json"
+	dumpFormatYAML     = "yaml"
+	dumpFormatManifest = "manifest"
+	dumpFormatShell    = "shell"
+	dumpFormatDocker   = "docker"
+	dumpFormatSystemd  = "systemd"
 )
 
 type dumpOpts struct {
 	filename string
+	format   string
 	noquotes bool
+	output   string
 }
 
 func newDumpCmd(out io.Writer) *cobra.Command {
@@ -24,8 +40,8 @@ func newDumpCmd(out io.Writer) *cobra.Command {
 
 	dumpCmd := &cobra.Command{
 		Use:   "dump [NAME]",
-		Short: "Dump secrets as dotenv (key=value) format",
-		Long: `Dump secrets as dotenv (key=value) format
+		Short: "Dump secrets as dotenv (key=value) format, environment files or Secret manifests",
+		Long: `Dump secrets as dotenv (key=value) format, environment files or Secret manifests
 
 $ k8sec dump rails
 database-url="postgres://example.com:5432/dbname"
@@ -41,6 +57,60 @@ Save as .env without quotes:
 $ k8sec dump -f .env --noquotes rails
 $ cat .env
 database-url=postgres://example.com:5432/dbname
+
+Dump as environment file. "shell" is evaluated with "source" or "eval" in POSIX shells, "docker" is read by
+"docker run --env-file" and "systemd" is read by EnvironmentFile= of systemd units. An error is returned if a key or
+value cannot be represented in the format, e.g. a key which is not a valid variable name, or a value with newlines in
+"docker" format:
+
+$ k8sec dump --format shell rails
+Error: dump secret "rails": the key database-url cannot be used as shell variable name
+$ k8sec dump --format shell postgres
+export database='dbname'
+export host='example.com'
+export password='p@ssw0rd'
+export username='dtan4'
+$ eval "$(k8sec dump --format shell postgres)"
+$ k8sec dump --format docker postgres > postgres.env
+$ docker run --env-file postgres.env postgres
+$ k8sec dump --format systemd postgres
+database="dbname"
+host="example.com"
+password="p@ssw0rd"
+username="dtan4"
+
+Dump as JSON object or YAML mapping of key to value, which can be loaded with "k8sec load". Values which are not valid
+UTF-8 are dumped as {"base64": "..."}:
+
+$ k8sec dump --format json rails
+{
+  "database-url": "postgres://example.com:5432/dbname",
+  "logo.png": {
+    "base64": "iVBORw0KGgo="
+  }
+}
+
+Dump as Secret manifest, which can be applied with "kubectl apply" or loaded with "k8sec load". Server-populated
+fields such as resourceVersion and uid, and namespace are stripped. All secrets are dumped as multi-document YAML if
+NAME is omitted:
+
+$ k8sec dump --format manifest rails
+apiVersion: v1
+data:
+  database-url: cG9zdGdyZXM6Ly9leGFtcGxlLmNvbTo1NDMyL2RibmFtZQ==
+kind: Secret
+metadata:
+  labels:
+    app: rails
+  name: rails
+type: Opaque
+
+Render with go-template or JSONPath template. Values in data are decoded:
+
+$ k8sec dump -o go-template='postgres://{{.data.username}}:{{.data.password}}@{{.data.host}}/{{.data.database}}' postgres
+postgres://dtan4:p@ssw0rd@example.com/dbname
+$ k8sec dump -o jsonpath='{.data.password}' postgres
+p@ssw0rd
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 1 {
@@ -49,9 +119,9 @@ database-url=postgres://example.com:5432/dbname
 
 			ctx := context.Background()
 
-			k8sclient, err := client.New(rootOpts.kubeconfig, rootOpts.context)
+			k8sclient, err := newClient(out)
 			if err != nil {
-				return fmt.Errorf("initialize Kubernetes API client: %w", err)
+				return err
 			}
 
 			var namespace string
@@ -67,13 +137,108 @@ database-url=postgres://example.com:5432/dbname
 	}
 
 	dumpCmd.Flags().StringVarP(&opts.filename, "filename", "f", "", "File to dump")
+	dumpCmd.Flags().StringVar(&opts.format, "format", dumpFormatDotenv, `Format of the dump. One of: "dotenv", "shell", "docker", "systemd", "json", "yaml" or "manifest"`)
 	dumpCmd.Flags().BoolVar(&opts.noquotes, "noquotes", false, "Dump without quotes")
+	dumpCmd.Flags().StringVarP(&opts.output, "output", "o", "", "Render with template instead of dotenv format. One of: go-template=TEMPLATE|jsonpath=TEMPLATE")
 
 	return dumpCmd
 }
 
 func runDump(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *dumpOpts) error {
-	var lines []string
+	var buf bytes.Buffer
+
+	format := opts.format
+	if format == "" {
+		format = dumpFormatDotenv
+	}
+
+	if opts.output != "" && format != dumpFormatDotenv {
+		return fmt.Errorf("--output cannot be used with --format %s", format)
+	}
+
+	if opts.noquotes && format != dumpFormatDotenv {
+		return fmt.Errorf("--noquotes cannot be used with --format %s", format)
+	}
+
+	if opts.output != "" {
+		format, err := parseOutputFormat(opts.output)
+		if err != nil {
+			return err
+		}
+
+		if !format.template() {
+			return fmt.Errorf("dump supports only go-template and jsonpath output, got %q", opts.output)
+		}
+
+		// Values are rendered as they are, without quotes
+		decode := func(value []byte) string {
+			return string(value)
+		}
+
+		if err := printSecretsTemplate(ctx, k8sclient, namespace, args, &buf, format, decode); err != nil {
+			return err
+		}
+	} else {
+		switch format {
+		case dumpFormatDotenv, dumpFormatShell, dumpFormatDocker, dumpFormatSystemd:
+			if err := dumpLines(ctx, k8sclient, namespace, args, &buf, envLineFunc(format, opts.noquotes)); err != nil {
+				return err
+			}
+		case dumpFormatJSON, dumpFormatYAML:
+			if err := dumpKeyValues(ctx, k8sclient, namespace, args, &buf, format); err != nil {
+				return err
+			}
+		case dumpFormatManifest:
+			if err := dumpManifests(ctx, k8sclient, namespace, args, &buf); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("--format must be one of %q, %q, %q, %q, %q, %q or %q, got %q", dumpFormatDotenv, dumpFormatShell, dumpFormatDocker, dumpFormatSystemd, dumpFormatJSON, dumpFormatYAML, dumpFormatManifest, format)
+		}
+	}
+
+	if opts.filename != "" {
+		f, err := os.Create(opts.filename)
+		if err != nil {
+			return fmt.Errorf("open file %q: %w", opts.filename, err)
+		}
+		defer f.Close()
+
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("write to file %q: %w", opts.filename, err)
+		}
+	} else {
+		if _, err := out.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// envLineFunc returns the function which formats a key-value pair as a line of the format
+func envLineFunc(format string, noquotes bool) func(key string, value []byte) (string, error) {
+	switch format {
+	case dumpFormatShell:
+		return envfile.ShellLine
+	case dumpFormatDocker:
+		return envfile.DockerLine
+	case dumpFormatSystemd:
+		return envfile.SystemdLine
+	default:
+		return func(key string, value []byte) (string, error) {
+			if noquotes {
+				return key + "=" + string(value), nil
+			}
+
+			return key + "=" + dotenv.Quote(string(value)), nil
+		}
+	}
+}
+
+// dumpLines dumps a line per key-value pair, sorted by line
+func dumpLines(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, lineFunc func(key string, value []byte) (string, error)) error {
+	var secrets []v1.Secret
 
 	if len(args) == 1 {
 		secret, err := k8sclient.GetSecret(ctx, namespace, args[0])
@@ -81,54 +246,114 @@ func runDump(ctx context.Context, k8sclient client.Client, namespace string, arg
 			return fmt.Errorf("get secret %q: %w", args[0], err)
 		}
 
+		secrets = append(secrets, *secret)
+	} else {
+		ss, err := k8sclient.ListSecrets(ctx, namespace)
+		if err != nil {
+			return fmt.Errorf("list secret: %w", err)
+		}
+
+		secrets = ss.Items
+	}
+
+	var lines []string
+
+	for _, secret := range secrets {
 		for key, value := range secret.Data {
-			line := string(value)
-			if !opts.noquotes {
-				line = strconv.Quote(line)
+			line, err := lineFunc(key, value)
+			if err != nil {
+				return fmt.Errorf("dump secret %q: %w", secret.Name, err)
 			}
-			lines = append(lines, key+"="+line)
+
+			lines = append(lines, line)
 		}
+	}
+
+	sort.Strings(lines)
+
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+
+	return nil
+}
+
+func dumpManifests(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer) error {
+	var secrets []*v1.Secret
+
+	if len(args) == 1 {
+		secret, err := k8sclient.GetSecret(ctx, namespace, args[0])
+		if err != nil {
+			return fmt.Errorf("get secret %q: %w", args[0], err)
+		}
+
+		secrets = append(secrets, secret)
 	} else {
-		secrets, err := k8sclient.ListSecrets(ctx, namespace)
+		ss, err := k8sclient.ListSecrets(ctx, namespace)
 		if err != nil {
 			return fmt.Errorf("list secret: %w", err)
 		}
 
-		for _, secret := range secrets.Items {
-			for key, value := range secret.Data {
-				v := string(value)
-				if !opts.noquotes {
-					v = strconv.Quote(v)
-				}
-				lines = append(lines, key+"="+v)
-			}
+		for i := range ss.Items {
+			secrets = append(secrets, &ss.Items[i])
 		}
+
+		sort.Slice(secrets, func(i, j int) bool {
+			return secrets[i].Name < secrets[j].Name
+		})
 	}
 
-	sort.Strings(lines)
+	return writeSecretManifests(out, secrets)
+}
 
-	if opts.filename != "" {
-		f, err := os.Create(opts.filename)
+// dumpKeyValues dumps data as JSON or YAML. Keys of all secrets are merged if NAME is omitted.
+func dumpKeyValues(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, format string) error {
+	var data map[string][]byte
+
+	if len(args) == 1 {
+		secret, err := k8sclient.GetSecret(ctx, namespace, args[0])
 		if err != nil {
-			return fmt.Errorf("open file %q: %w", opts.filename, err)
+			return fmt.Errorf("get secret %q: %w", args[0], err)
 		}
-		defer f.Close()
 
-		w := bufio.NewWriter(f)
+		data = secret.Data
+	} else {
+		secrets, err := k8sclient.ListSecrets(ctx, namespace)
+		if err != nil {
+			return fmt.Errorf("list secret: %w", err)
+		}
 
-		for _, line := range lines {
-			_, err := w.WriteString(line + "\n")
-			if err != nil {
-				return fmt.Errorf("write to file %q: %w", opts.filename, err)
+		data = map[string][]byte{}
+		sources := map[string]string{}
+
+		for _, secret := range secrets.Items {
+			for key, value := range secret.Data {
+				if src, ok := sources[key]; ok && !bytes.Equal(data[key], value) {
+					return fmt.Errorf("the key %s exists in secrets %q and %q with different values", key, src, secret.Name)
+				}
+
+				data[key] = value
+				sources[key] = secret.Name
 			}
 		}
+	}
+
+	var (
+		b   []byte
+		err error
+	)
 
-		w.Flush()
+	if format == dumpFormatJSON {
+		b, err = keyvalue.EncodeJSON(data)
 	} else {
-		for _, line := range lines {
-			fmt.Fprintln(out, line)
-		}
+		b, err = keyvalue.EncodeYAML(data)
 	}
 
-	return nil
+	if err != nil {
+		return fmt.Errorf("encode as %s: %w", format, err)
+	}
+
+	_, err = out.Write(b)
+
+	return err
 }
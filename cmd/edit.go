@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/dotenv"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	editFormatDotenv = "dotenv"
+	editFormatYAML   = "yaml"
+
+	defaultEditor = "vi"
+)
+
+type editOpts struct {
+	format     string
+	showValues bool
+	// editor opens the file at path and returns after it is closed
+	editor func(path string) error
+}
+
+func newEditCmd(out io.Writer) *cobra.Command {
+	opts := editOpts{
+		editor: runEditor,
+	}
+
+	editCmd := &cobra.Command{
+		Use:   "edit NAME",
+		Short: "Edit secret with decoded values in editor",
+		Long: `Edit secret with decoded values in editor
+
+The data of the secret is written to a temporary file only you can read, in dotenv format by default or in YAML format
+with --format yaml, and the file is opened with $EDITOR (vi if not set). After the editor is closed, the changed keys are
+printed and the secret is updated:
+
+$ k8sec edit rails
+~ database-url=******** => ********
+rails
+
+If the file is invalid, or the secret has been modified by others since it was got, the editor is opened again with the
+error in the header comment. Save an empty file to cancel editing. The file is removed when the editor is closed, or when
+k8sec receives SIGINT or SIGTERM.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runEdit(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	editCmd.Flags().StringVar(&opts.format, "format", editFormatDotenv, `Format of the file to edit. One of: "dotenv" or "yaml"`)
+	editCmd.Flags().BoolVar(&opts.showValues, "show-values", false, "Show values of changed keys instead of masking them")
+
+	return editCmd
+}
+
+func runEdit(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *editOpts) error {
+	name := args[0]
+
+	if opts.format != editFormatDotenv && opts.format != editFormatYAML {
+		return fmt.Errorf("--format must be one of %q or %q, got %q", editFormatDotenv, editFormatYAML, opts.format)
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get secret %q: %w", name, err)
+	}
+
+	body, err := encodeEditData(s.Data, opts.format)
+	if err != nil {
+		return fmt.Errorf("secret %q: %w", name, err)
+	}
+
+	// Signals are caught while the file exists so that it is removed before exiting
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	// The file is created with 0600 permission
+	f, err := os.CreateTemp("", "k8sec-edit-*."+map[string]string{editFormatDotenv: "env", editFormatYAML: "yaml"}[opts.format])
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	var editErr error
+
+	for {
+		content := editHeader(name, namespace, editErr) + body
+
+		if err := os.WriteFile(f.Name(), []byte(content), 0600); err != nil {
+			return fmt.Errorf("write temporary file: %w", err)
+		}
+
+		editorErr := opts.editor(f.Name())
+
+		select {
+		case sig := <-sigCh:
+			return &exitError{code: 128 + int(sig.(syscall.Signal))}
+		default:
+		}
+
+		if editorErr != nil {
+			return fmt.Errorf("run editor: %w", editorErr)
+		}
+
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			return fmt.Errorf("read temporary file: %w", err)
+		}
+
+		body = stripEditHeader(string(b))
+
+		if strings.TrimSpace(body) == "" {
+			fmt.Fprintln(out, "Edit cancelled, saved file was empty.")
+			return nil
+		}
+
+		// Comments are ignored in both formats, so the whole file is parsed to report the correct line numbers
+		data, err := decodeEditData(string(b), filepath.Base(f.Name()), opts.format)
+		if err != nil {
+			editErr = err
+			continue
+		}
+
+		changes := diffData(s.Data, data)
+		if changes.empty() {
+			fmt.Fprintln(out, "Edit cancelled, no changes made.")
+			return nil
+		}
+
+		// The secret is updated only if it has not been modified since it was got
+		_, err = updateSecret(ctx, k8sclient, namespace, s.DeepCopy(), &updateOpts{ifResourceVersion: s.ResourceVersion}, func(s *v1.Secret) error {
+			// Expiry is checked only if the certificate is changed, as set does
+			var now time.Time
+			if !bytes.Equal(s.Data[v1.TLSCertKey], data[v1.TLSCertKey]) {
+				now = time.Now()
+			}
+
+			s.Data = data
+
+			return secrettype.Validate(s, now)
+		})
+		if err != nil {
+			editErr = fmt.Errorf("update secret %q: %w", name, err)
+
+			if apierrors.IsConflict(err) {
+				latest, err := k8sclient.GetSecret(ctx, namespace, name)
+				if err != nil {
+					return fmt.Errorf("get latest secret %q: %w", name, err)
+				}
+
+				s = latest
+				editErr = fmt.Errorf("%w\nThe secret has been modified by others. Your changes are kept below, and saving them overwrites the latest secret", editErr)
+			}
+
+			continue
+		}
+
+		printDataDiff(out, s.Data, data, changes, opts.showValues)
+		if !isDryRun(k8sclient) {
+			fmt.Fprintln(out, name)
+		}
+
+		return nil
+	}
+}
+
+// editHeader returns the comment placed at the beginning of the file to edit
+func editHeader(name, namespace string, err error) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Please edit the data of secret %q in namespace %q.\n", name, namespace)
+	sb.WriteString("# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.\n")
+
+	if err != nil {
+		sb.WriteString("#\n")
+
+		for _, line := range strings.Split(err.Error(), "\n") {
+			sb.WriteString("# error: " + line + "\n")
+		}
+	}
+
+	sb.WriteString("#\n")
+
+	return sb.String()
+}
+
+// stripEditHeader removes the comment lines at the beginning of the edited file
+func stripEditHeader(content string) string {
+	for strings.HasPrefix(content, "#") {
+		i := strings.IndexByte(content, '\n')
+		if i < 0 {
+			return ""
+		}
+
+		content = content[i+1:]
+	}
+
+	return content
+}
+
+// encodeEditData encodes secret data in the given format
+func encodeEditData(data map[string][]byte, format string) (string, error) {
+	keys := make([]string, 0, len(data))
+
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	if format == editFormatYAML {
+		m := make(map[string]string, len(data))
+
+		for _, k := range keys {
+			if !utf8.Valid(data[k]) {
+				return "", fmt.Errorf("value of key %s is not valid UTF-8 text, use --format %s instead", k, editFormatDotenv)
+			}
+
+			m[k] = string(data[k])
+		}
+
+		b, err := yaml.Marshal(m)
+		if err != nil {
+			return "", fmt.Errorf("encode data as YAML: %w", err)
+		}
+
+		return string(b), nil
+	}
+
+	var sb strings.Builder
+
+	for _, k := range keys {
+		sb.WriteString(k + "=" + dotenv.Quote(string(data[k])) + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// decodeEditData decodes the edited file in the given format
+func decodeEditData(content, filename, format string) (map[string][]byte, error) {
+	if format == editFormatYAML {
+		var m map[string]interface{}
+
+		if err := yaml.Unmarshal([]byte(content), &m); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+
+		data := make(map[string][]byte, len(m))
+
+		for k, v := range m {
+			if errs := validation.IsConfigMapKey(k); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid key %q: %s", k, strings.Join(errs, ", "))
+			}
+
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("value of key %s must be a string, quote it", k)
+			}
+
+			data[k] = []byte(s)
+		}
+
+		return data, nil
+	}
+
+	entries, err := dotenv.Parse(strings.NewReader(content), filename, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte, len(entries))
+
+	for _, e := range entries {
+		data[e.Key] = []byte(e.Value)
+	}
+
+	return data, nil
+}
+
+// runEditor opens the file with $EDITOR, which can contain arguments (e.g. "code --wait")
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{defaultEditor}
+	}
+
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Start(); err != nil {
+		return err
+	}
+
+	// SIGINT from terminal is sent to the editor directly, while SIGTERM may be sent to k8sec only
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case sig := <-sigCh:
+			c.Process.Signal(sig)
+		case <-done:
+		}
+	}()
+
+	return c.Wait()
+}
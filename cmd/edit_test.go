@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunEdit(t *testing.T) {
+	testcases := map[string]struct {
+		format     string
+		secretType v1.SecretType
+		conflicts  int
+		// edits are written to the file each time the editor is opened
+		edits []string
+		// wantFiles are the contents of the file each time the editor is opened, checked only if not empty
+		wantFiles []string
+		wantData  map[string][]byte
+		wantOut   string
+		wantErr   error
+	}{
+		"edit as dotenv": {
+			format: editFormatDotenv,
+			edits: []string{
+				`database-url="postgres://example.com:5432/newdb"
+rails-env=production
+private-key='$ecret'
+`,
+			},
+			wantFiles: []string{
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+database-url="postgres://example.com:5432/dbname"
+password="p@ss\$w0rd\n"
+rails-env="production"
+`,
+			},
+			wantData: map[string][]byte{
+				"database-url": []byte("postgres://example.com:5432/newdb"),
+				"rails-env":    []byte("production"),
+				"private-key":  []byte("$ecret"),
+			},
+			wantOut: `+ private-key=********
+~ database-url=******** => ********
+- password=********
+rails
+`,
+		},
+
+		"edit as YAML": {
+			format: editFormatYAML,
+			edits: []string{
+				`database-url: postgres://example.com:5432/dbname
+password: "p@ss$w0rd\n"
+rails-env: staging
+`,
+			},
+			wantFiles: []string{
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+database-url: postgres://example.com:5432/dbname
+password: |
+  p@ss$w0rd
+rails-env: production
+`,
+			},
+			wantData: map[string][]byte{
+				"database-url": []byte("postgres://example.com:5432/dbname"),
+				"password":     []byte("p@ss$w0rd\n"),
+				"rails-env":    []byte("staging"),
+			},
+			wantOut: `~ rails-env=******** => ********
+rails
+`,
+		},
+
+		"reopen with parse error": {
+			format: editFormatDotenv,
+			edits: []string{
+				`rails-env="staging
+`,
+				`rails-env=staging
+`,
+			},
+			wantFiles: []string{
+				"",
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+# error: k8sec-edit.env:1: unterminated double-quoted value
+#
+rails-env="staging
+`,
+			},
+			wantData: map[string][]byte{
+				"rails-env": []byte("staging"),
+			},
+			wantOut: `~ rails-env=******** => ********
+- database-url=********
+- password=********
+rails
+`,
+		},
+
+		"reopen with invalid YAML value": {
+			format: editFormatYAML,
+			edits: []string{
+				`port: 5432
+`,
+				`port: "5432"
+`,
+			},
+			wantFiles: []string{
+				"",
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+# error: value of key port must be a string, quote it
+#
+port: 5432
+`,
+			},
+			wantData: map[string][]byte{
+				"port": []byte("5432"),
+			},
+			wantOut: `+ port=********
+- database-url=********
+- password=********
+- rails-env=********
+rails
+`,
+		},
+
+		"reopen with conflict": {
+			format:    editFormatDotenv,
+			conflicts: 1,
+			edits: []string{
+				`rails-env=staging
+`,
+				`rails-env=staging
+`,
+			},
+			wantFiles: []string{
+				"",
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+# error: update secret "rails": Operation cannot be fulfilled on secrets "rails": <nil>
+# error: The secret has been modified by others. Your changes are kept below, and saving them overwrites the latest secret
+#
+rails-env=staging
+`,
+			},
+			wantData: map[string][]byte{
+				"rails-env": []byte("staging"),
+			},
+			wantOut: `~ rails-env=******** => ********
+- database-url=********
+- password=********
+rails
+`,
+		},
+
+		"reopen with invalid typed secret": {
+			format:     editFormatDotenv,
+			secretType: v1.SecretTypeBasicAuth,
+			edits: []string{
+				`password=p@ssw0rd
+`,
+				`username=dtan4
+password=p@ssw0rd
+`,
+			},
+			wantFiles: []string{
+				"",
+				`# Please edit the data of secret "rails" in namespace "test".
+# Lines beginning with '#' at the top of this file are ignored, and an empty file cancels editing.
+#
+# error: update secret "rails": secret of type kubernetes.io/basic-auth must have key username
+#
+password=p@ssw0rd
+`,
+			},
+			wantData: map[string][]byte{
+				"username": []byte("dtan4"),
+				"password": []byte("p@ssw0rd"),
+			},
+			wantOut: `+ username=********
+~ password=******** => ********
+- database-url=********
+- rails-env=********
+rails
+`,
+		},
+
+		"cancel with empty file": {
+			format: editFormatDotenv,
+			edits: []string{
+				"# comment only\n",
+			},
+			wantOut: "Edit cancelled, saved file was empty.\n",
+		},
+
+		"cancel without changes": {
+			format: editFormatDotenv,
+			edits: []string{
+				`database-url=postgres://example.com:5432/dbname
+password="p@ss\$w0rd\n"
+rails-env=production
+`,
+			},
+			wantOut: "Edit cancelled, no changes made.\n",
+		},
+
+		"unknown format": {
+			format:  "toml",
+			wantErr: errors.New(`--format must be one of "dotenv" or "yaml", got "toml"`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &conflictingClient{
+				fakeClient: fakeClient{
+					getSecretResponse: &v1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "rails",
+							ResourceVersion: "12345",
+						},
+						Type: tc.secretType,
+						Data: map[string][]byte{
+							"database-url": []byte("postgres://example.com:5432/dbname"),
+							"password":     []byte("p@ss$w0rd\n"),
+							"rails-env":    []byte("production"),
+						},
+					},
+				},
+				conflicts: tc.conflicts,
+			}
+
+			var files []string
+
+			opts := &editOpts{
+				format: tc.format,
+				editor: func(path string) error {
+					b, err := os.ReadFile(path)
+					if err != nil {
+						return err
+					}
+
+					// The random part of the file name is removed to check error messages
+					base := filepath.Base(path)
+					files = append(files, strings.ReplaceAll(string(b), base, "k8sec-edit"+filepath.Ext(base)))
+
+					if len(files) > len(tc.edits) {
+						return errors.New("editor is opened too many times")
+					}
+
+					return os.WriteFile(path, []byte(tc.edits[len(files)-1]), 0600)
+				},
+			}
+
+			var out bytes.Buffer
+
+			err := runEdit(context.Background(), k8sclient, namespace, []string{"rails"}, &out, opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			for i, want := range tc.wantFiles {
+				if want != "" && files[i] != want {
+					t.Logf("want:\n%s", want)
+					t.Logf("got:\n%s", files[i])
+					t.Fatalf("file #%d want %q, got %q", i, want, files[i])
+				}
+			}
+
+			var gotData map[string][]byte
+			if k8sclient.updatedSecret != nil {
+				gotData = k8sclient.updatedSecret.Data
+			}
+
+			if !reflect.DeepEqual(gotData, tc.wantData) {
+				t.Fatalf("want data %#v, got %#v", tc.wantData, gotData)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Logf("want:\n%s", tc.wantOut)
+				t.Logf("got:\n%s", out.String())
+				t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestRunEditSignal(t *testing.T) {
+	// This test is not run in parallel since the signal is sent to the whole process
+	k8sclient := &fakeClient{
+		getSecretResponse: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rails",
+			},
+			Data: map[string][]byte{
+				"password": []byte("p@ssw0rd"),
+			},
+		},
+	}
+
+	var path string
+
+	opts := &editOpts{
+		format: editFormatDotenv,
+		editor: func(p string) error {
+			path = p
+
+			proc, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				return err
+			}
+
+			if err := proc.Signal(syscall.SIGTERM); err != nil {
+				t.Skipf("cannot send SIGTERM: %s", err)
+			}
+
+			// wait for the signal to be delivered
+			time.Sleep(100 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	var out bytes.Buffer
+
+	err := runEdit(context.Background(), k8sclient, "test", []string{"rails"}, &out, opts)
+
+	var eerr *exitError
+	if !errors.As(err, &eerr) || eerr.code != 128+int(syscall.SIGTERM) {
+		t.Fatalf("want exit error with code %d, got %v", 128+int(syscall.SIGTERM), err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("want temporary file %s removed, got %v", path, err)
+	}
+
+	if k8sclient.updatedSecret != nil {
+		t.Fatalf("want no secret updated, got %#v", k8sclient.updatedSecret)
+	}
+}
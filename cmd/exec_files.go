@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// secretDirEnv is the environment variable which has the path of the directory containing secret files
+	secretDirEnv = "K8SEC_SECRET_DIR"
+	// secretDirPlaceholder in command arguments is replaced with the path of the directory
+	secretDirPlaceholder = "{}"
+	// sharedMemoryDir is tmpfs on most Linux systems, where files are never written to disk
+	sharedMemoryDir = "/dev/shm"
+)
+
+type execFilesOpts struct {
+	tmpDir string
+}
+
+func newExecFilesCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := execFilesOpts{}
+
+	execFilesCmd := &cobra.Command{
+		Use:   "exec-files NAME -- COMMAND [ARGS ...]",
+		Short: "Run command with secret keys as files in temporary directory",
+		Long: `Run command with secret keys as files in temporary directory
+
+Each key of the secret is written to a file in a temporary directory only you can access, as Pod sees secret volume.
+The path of the directory is set to $K8SEC_SECRET_DIR, and {} in the arguments is replaced with it:
+
+$ k8sec exec-files nginx -- sh -c 'ls $K8SEC_SECRET_DIR'
+tls.crt
+tls.key
+$ k8sec exec-files nginx -- openssl x509 -noout -subject -in {}/tls.crt
+
+The directory is created in /dev/shm (tmpfs on most Linux systems) if available, otherwise in the default directory for
+temporary files. It is removed when the command exits, or when k8sec receives SIGINT or SIGTERM. The signal is
+forwarded to the command. The exit status of the command is passed through.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return errors.New("command must be specified after --")
+			}
+
+			if dash != 1 {
+				return errors.New("one secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runExecFiles(ctx, k8sclient, namespace, args[0], args[dash:], in, out, &opts)
+		},
+	}
+
+	execFilesCmd.Flags().StringVar(&opts.tmpDir, "tmpdir", "", "Directory to create the temporary directory in. /dev/shm is used if available")
+
+	return execFilesCmd
+}
+
+func runExecFiles(ctx context.Context, k8sclient client.Client, namespace, name string, command []string, in io.Reader, out io.Writer, opts *execFilesOpts) error {
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get secret %q: %w", name, err)
+	}
+
+	// Signals are caught until the command starts so that the directory is removed
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	tmpDir := opts.tmpDir
+	if tmpDir == "" {
+		tmpDir = defaultSecretTmpDir()
+	}
+
+	// The directory is created with 0700 permission
+	dir, err := os.MkdirTemp(tmpDir, "k8sec-")
+	if err != nil {
+		return fmt.Errorf("create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for k, v := range s.Data {
+		if k == "." || k == ".." || strings.ContainsAny(k, `/\`) {
+			return fmt.Errorf("the key %s cannot be used as file name", k)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, k), v, 0600); err != nil {
+			return fmt.Errorf("write file for key %s: %w", k, err)
+		}
+	}
+
+	select {
+	case sig := <-sigCh:
+		return &exitError{code: 128 + int(sig.(syscall.Signal))}
+	default:
+	}
+
+	args := make([]string, 0, len(command))
+
+	for _, arg := range command {
+		args = append(args, strings.ReplaceAll(arg, secretDirPlaceholder, dir))
+	}
+
+	return execCommand(args, append(os.Environ(), secretDirEnv+"="+dir), in, out)
+}
+
+// defaultSecretTmpDir returns /dev/shm if available, otherwise the default directory for temporary files
+func defaultSecretTmpDir() string {
+	if fi, err := os.Stat(sharedMemoryDir); err == nil && fi.IsDir() {
+		return sharedMemoryDir
+	}
+
+	return os.TempDir()
+}
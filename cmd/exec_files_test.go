@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunExecFiles(t *testing.T) {
+	testcases := map[string]struct {
+		data    map[string][]byte
+		command []string
+		wantOut string
+		wantErr error
+	}{
+		"files": {
+			data: map[string][]byte{
+				"tls.crt": []byte("-----BEGIN CERTIFICATE-----\n"),
+				"tls.key": {0x00, 0xff},
+			},
+			command: []string{"sh", "-c", `test "$K8SEC_SECRET_DIR" = "$1" && ls "$1" && cat "$1/tls.crt" && od -An -tx1 "$1/tls.key" && stat -c %a "$1" "$1/tls.key"`, "sh", "{}"},
+			wantOut: `tls.crt
+tls.key
+-----BEGIN CERTIFICATE-----
+ 00 ff
+700
+600
+`,
+		},
+
+		"exit status": {
+			data: map[string][]byte{
+				"tls.crt": []byte("-----BEGIN CERTIFICATE-----\n"),
+			},
+			command: []string{"sh", "-c", "exit 2"},
+			wantErr: &exitError{code: 2},
+		},
+
+		"invalid key": {
+			data: map[string][]byte{
+				"..": []byte("foo"),
+			},
+			command: []string{"true"},
+			wantErr: errors.New("the key .. cannot be used as file name"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "nginx",
+					},
+					Data: tc.data,
+				},
+			}
+
+			tmpDir := t.TempDir()
+
+			var out bytes.Buffer
+
+			err := runExecFiles(context.Background(), k8sclient, namespace, "nginx", tc.command, strings.NewReader(""), &out, &execFilesOpts{tmpDir: tmpDir})
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if out.String() != tc.wantOut {
+					t.Logf("want:\n%s", tc.wantOut)
+					t.Logf("got:\n%s", out.String())
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+
+			entries, err := os.ReadDir(tmpDir)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if len(entries) > 0 {
+				t.Fatalf("want temporary directory removed, got %v", entries)
+			}
+		})
+	}
+}
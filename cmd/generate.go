@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/generate"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type generateOpts struct {
+	force  bool
+	update updateOpts
+}
+
+func newGenerateCmd(out io.Writer) *cobra.Command {
+	opts := generateOpts{}
+
+	generateCmd := &cobra.Command{
+		Use:   "generate NAME KEY1[:SPEC1] [KEY2[:SPEC2] ...]",
+		Short: "Set random values generated securely",
+		Long: `Set random values generated securely
+
+Values are generated with cryptographically secure random number generator, so they never appear in shell history.
+The secret is created if it does not exist. Existing keys are not overwritten unless --force is given:
+
+$ k8sec generate rails secret-key-base:hex,64 database-password
+rails
+
+SPEC is comma-separated options:
+
+  password, hex, base64, base64url, uuid, passphrase  format (default: password)
+  N or length=N                                       number of characters for password (default: 32),
+                                                      bytes for hex and base64 (default: 32),
+                                                      or words for passphrase (default: 6)
+  charset=CLASS[+CLASS...]                            character classes of password: lower, upper, digit, symbol,
+                                                      alnum (default) or all
+  alphabet=CHARS                                      characters of password, which cannot contain ","
+  separator=SEP                                       separator of passphrase words (default: "-")
+
+$ k8sec generate rails admin-password:16,charset=all api-token:base64url session-id:uuid recovery:passphrase,4
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("too few arguments")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runGenerate(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	generateCmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite existing keys")
+	addUpdateFlags(generateCmd.Flags(), &opts.update)
+
+	return generateCmd
+}
+
+func runGenerate(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *generateOpts) error {
+	name := args[0]
+
+	keys, data, err := generateValues(args[1:])
+	if err != nil {
+		return err
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get secret %q: %w", name, err)
+		}
+
+		if opts.update.ifResourceVersion != "" {
+			return fmt.Errorf("secret %q does not exist", name)
+		}
+
+		s = &v1.Secret{
+			Type: v1.SecretTypeOpaque,
+			Data: data,
+		}
+		s.SetName(name)
+
+		if _, err := k8sclient.CreateSecret(ctx, namespace, s); err != nil {
+			return fmt.Errorf("create secret %q: %w", name, err)
+		}
+
+		if !isDryRun(k8sclient) {
+			fmt.Fprintln(out, name)
+		}
+
+		return nil
+	}
+
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		if s.Data == nil {
+			s.Data = map[string][]byte{}
+		}
+
+		for _, k := range keys {
+			if _, ok := s.Data[k]; ok && !opts.force {
+				return fmt.Errorf("the key %s already exists, use --force to overwrite it", k)
+			}
+
+			s.Data[k] = data[k]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
+
+// generateValues generates values for the arguments in KEY[:SPEC] format.
+// Values are generated before updating the secret so that the same values are used on retries.
+func generateValues(args []string) ([]string, map[string][]byte, error) {
+	keys := make([]string, 0, len(args))
+	data := make(map[string][]byte, len(args))
+
+	for _, arg := range args {
+		k, s, _ := strings.Cut(arg, ":")
+		if k == "" {
+			return nil, nil, errors.New("argument should be in KEY[:SPEC] format")
+		}
+
+		if _, ok := data[k]; ok {
+			return nil, nil, fmt.Errorf("the key %s is specified more than once", k)
+		}
+
+		spec, err := generate.ParseSpec(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse spec of key %s: %w", k, err)
+		}
+
+		v, err := generate.Generate(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate value of key %s: %w", k, err)
+		}
+
+		keys = append(keys, k)
+		data[k] = []byte(v)
+	}
+
+	return keys, data, nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunGenerate(t *testing.T) {
+	testcases := map[string]struct {
+		args         []string
+		opts         generateOpts
+		secret       *v1.Secret
+		getSecretErr error
+		// wantData has patterns of values
+		wantData    map[string]string
+		wantCreated bool
+		wantErr     error
+	}{
+		"create new secret": {
+			args:         []string{"rails", "secret-key-base:hex,16", "password"},
+			getSecretErr: apierrors.NewNotFound(v1.Resource("secrets"), "rails"),
+			wantData: map[string]string{
+				"secret-key-base": `^[0-9a-f]{32}$`,
+				"password":        `^[a-zA-Z0-9]{32}$`,
+			},
+			wantCreated: true,
+		},
+
+		"add to existing secret": {
+			args: []string{"rails", "session-id:uuid"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"rails-env": []byte("production"),
+				},
+			},
+			wantData: map[string]string{
+				"rails-env":  `^production$`,
+				"session-id": `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+			},
+		},
+
+		"overwrite with --force": {
+			args: []string{"rails", "rails-env:passphrase,3"},
+			opts: generateOpts{
+				force: true,
+			},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"rails-env": []byte("production"),
+				},
+			},
+			wantData: map[string]string{
+				"rails-env": `^[a-z]+-[a-z]+-[a-z]+$`,
+			},
+		},
+
+		"existing key without --force": {
+			args: []string{"rails", "rails-env"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"rails-env": []byte("production"),
+				},
+			},
+			wantErr: errors.New(`update secret "rails": the key rails-env already exists, use --force to overwrite it`),
+		},
+
+		"invalid spec": {
+			args:    []string{"rails", "password:rot13"},
+			wantErr: errors.New(`parse spec of key password: unknown format "rot13"`),
+		},
+
+		"duplicated key": {
+			args:    []string{"rails", "password", "password:hex"},
+			wantErr: errors.New("the key password is specified more than once"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: tc.secret,
+				getSecretErr:      tc.getSecretErr,
+			}
+
+			var out bytes.Buffer
+
+			err := runGenerate(context.Background(), k8sclient, namespace, tc.args, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			written := k8sclient.updatedSecret
+			if tc.wantCreated {
+				written = k8sclient.createdSecret
+			}
+
+			if written == nil {
+				t.Fatalf("want secret written, got nothing")
+			}
+
+			if len(written.Data) != len(tc.wantData) {
+				t.Fatalf("want %d keys, got %d", len(tc.wantData), len(written.Data))
+			}
+
+			for k, pattern := range tc.wantData {
+				if !regexp.MustCompile(pattern).Match(written.Data[k]) {
+					t.Errorf("want value of %s to match %s, got %q", k, pattern, written.Data[k])
+				}
+			}
+
+			if out.String() != "rails\n" {
+				t.Fatalf("want %q, got %q", "rails\n", out.String())
+			}
+		})
+	}
+}
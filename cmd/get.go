@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+type getOpts struct {
+	filename string
+}
+
+func newGetCmd(out io.Writer) *cobra.Command {
+	opts := getOpts{}
+
+	getCmd := &cobra.Command{
+		Use:   "get NAME KEY",
+		Short: "Print the raw value of a key in secret",
+		Long: `Print the raw value of a key in secret
+
+The value is written as it is, without quotes and trailing newline. Binary values and values containing newlines are
+kept as they are:
+
+$ k8sec get rails database-url
+postgres://example.com:5432/dbname
+$ export DATABASE_URL="$(k8sec get rails database-url)"
+
+Write the value to a file, which only you can read:
+
+$ k8sec get -f tls.key nginx tls.key
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("secret name and key must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runGet(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	getCmd.Flags().StringVarP(&opts.filename, "filename", "f", "", "File to write the value to")
+
+	return getCmd
+}
+
+func runGet(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *getOpts) error {
+	name, key := args[0], args[1]
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get secret %q: %w", name, err)
+	}
+
+	v, ok := s.Data[key]
+	if !ok {
+		return fmt.Errorf("the key %s does not exist in secret %q", key, name)
+	}
+
+	if opts.filename != "" {
+		return writeSecretFile(opts.filename, v)
+	}
+
+	if _, err := out.Write(v); err != nil {
+		return fmt.Errorf("write value: %w", err)
+	}
+
+	return nil
+}
+
+// writeSecretFile writes value to the file only you can read.
+// The permission of an existing file is changed before writing, since os.WriteFile sets it only when creating the file.
+func writeSecretFile(filename string, value []byte) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("open file %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	if err := f.Chmod(0600); err != nil {
+		return fmt.Errorf("change permission of file %q: %w", filename, err)
+	}
+
+	if _, err := f.Write(value); err != nil {
+		return fmt.Errorf("write file %q: %w", filename, err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dtan4/k8sec/pkg/dotenv"
+	"github.com/dtan4/k8sec/pkg/keyvalue"
+	"github.com/spf13/pflag"
+)
+
+// Formats of files loaded by load and compared by diff
+const (
+	loadFormatDotenv   = "dotenv"
+	loadFormatJSON     = "json"
+	loadFormatYAML     = "yaml"
+	loadFormatManifest = "manifest"
+)
+
+// defaultFlattenSeparator joins the keys of nested objects
+const defaultFlattenSeparator = "_"
+
+// inputOpts are the options to parse files given to load and diff
+type inputOpts struct {
+	format    string
+	flatten   bool
+	separator string
+	expandEnv bool
+}
+
+func addInputFlags(flags *pflag.FlagSet, opts *inputOpts) {
+	flags.StringVar(&opts.format, "format", "", `Format of the file. One of: "dotenv", "json", "yaml" or "manifest". Detected from the file if not specified`)
+	flags.BoolVar(&opts.flatten, "flatten", false, "Load nested objects in JSON and YAML with the keys joined with --separator")
+	flags.StringVar(&opts.separator, "separator", defaultFlattenSeparator, "Separator of the keys of nested objects flattened with --flatten")
+	flags.BoolVar(&opts.expandEnv, "expand-env", false, "Resolve variable references with process environment variables too")
+}
+
+// resolveInputFormat returns the format given by --format, or detected from filename and b if not given
+func resolveInputFormat(filename string, b []byte, opts *inputOpts) (string, error) {
+	format := opts.format
+	if format == "" {
+		format = detectLoadFormat(filename, b)
+	}
+
+	switch format {
+	case loadFormatDotenv, loadFormatJSON, loadFormatYAML:
+	case loadFormatManifest:
+		return format, nil
+	default:
+		return "", fmt.Errorf("--format must be one of %q, %q, %q or %q, got %q", loadFormatDotenv, loadFormatJSON, loadFormatYAML, loadFormatManifest, format)
+	}
+
+	if opts.flatten && opts.separator == "" {
+		return "", errors.New("--separator must not be empty")
+	}
+
+	return format, nil
+}
+
+// parseInputData parses b in dotenv, JSON or YAML format into secret data. source is used in error messages.
+// existing is the data of the secret used to resolve variable references in dotenv file.
+func parseInputData(b []byte, source, format string, existing map[string][]byte, opts *inputOpts) (map[string][]byte, error) {
+	kvOpts := keyvalue.Options{
+		Flatten:   opts.flatten,
+		Separator: opts.separator,
+	}
+
+	switch format {
+	case loadFormatJSON:
+		return keyvalue.ParseJSON(b, source, kvOpts)
+	case loadFormatYAML:
+		return keyvalue.ParseYAML(b, source, kvOpts)
+	default:
+		return parseDotenv(bytes.NewReader(b), source, existing, opts.expandEnv)
+	}
+}
+
+// detectLoadFormat returns manifest if the file has the extension of JSON or YAML and contains Secret manifest,
+// json or yaml if the file has the extension but is not manifest, otherwise dotenv
+func detectLoadFormat(filename string, b []byte) string {
+	if !hasManifestExtension(filename) {
+		return loadFormatDotenv
+	}
+
+	if isSecretManifest(b) {
+		return loadFormatManifest
+	}
+
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return loadFormatJSON
+	}
+
+	return loadFormatYAML
+}
+
+// readInput reads the whole content of file, or in if filename is empty.
+// The name of the source used in error messages is also returned.
+func readInput(in io.Reader, filename string) ([]byte, string, error) {
+	if filename == "" {
+		b, err := io.ReadAll(in)
+		if err != nil {
+			return nil, "", fmt.Errorf("read stdin: %w", err)
+		}
+
+		return b, "<stdin>", nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("open file %q: %w", filename, err)
+	}
+
+	return b, filename, nil
+}
+
+// parseDotenv parses dotenv text read from r. name is used in error messages.
+// Variable references are resolved with the keys defined earlier in the file first,
+// then with existing data and process environment variables if expandEnv is true.
+func parseDotenv(r io.Reader, name string, existing map[string][]byte, expandEnv bool) (map[string][]byte, error) {
+	lookup := func(key string) (string, bool) {
+		if v, ok := existing[key]; ok {
+			return string(v), true
+		}
+
+		if expandEnv {
+			return os.LookupEnv(key)
+		}
+
+		return "", false
+	}
+
+	entries, err := dotenv.Parse(r, name, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte, len(entries))
+
+	for _, e := range entries {
+		data[e.Key] = []byte(e.Value)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestDetectLoadFormat(t *testing.T) {
+	manifest := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: rails
+`)
+
+	testcases := map[string]struct {
+		filename string
+		content  []byte
+		want     string
+	}{
+		"YAML manifest": {
+			filename: "secret.yaml",
+			content:  manifest,
+			want:     loadFormatManifest,
+		},
+		"JSON List with uppercase extension": {
+			filename: "secret.JSON",
+			content:  []byte(`{"apiVersion": "v1", "kind": "List", "items": []}`),
+			want:     loadFormatManifest,
+		},
+		"manifest without extension": {
+			filename: "secret",
+			content:  manifest,
+			want:     loadFormatDotenv,
+		},
+		"YAML of other kind": {
+			filename: "deployment.yml",
+			content:  []byte("apiVersion: apps/v1\nkind: Deployment\n"),
+			want:     loadFormatYAML,
+		},
+		"JSON": {
+			filename: "config.json",
+			content:  []byte(`{"database-url": "postgres://example.com:5432/dbname"}`),
+			want:     loadFormatJSON,
+		},
+		"stdin": {
+			content: manifest,
+			want:    loadFormatDotenv,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := detectLoadFormat(tc.filename, tc.content); got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
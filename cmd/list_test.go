@@ -5,14 +5,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestRunList(t *testing.T) {
+	tlsCert, _ := newTestCertificate(t, "example.com", time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), "example.com", "www.example.com")
+
 	testcases := map[string]struct {
 		base64encode bool
+		output       string
 		args         []string
 		secret       *v1.Secret
 		secrets      *v1.SecretList
@@ -107,6 +111,241 @@ rails	Opaque	rails-env	cHJvZHVjdGlvbg==
 			wantErr: nil,
 		},
 
+		"one secret arg with -o wide": {
+			output: "wide",
+			args:   []string{"rails"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+					Labels: map[string]string{
+						"role": "web",
+						"app":  "rails",
+					},
+					CreationTimestamp: metav1.NewTime(time.Date(2022, 3, 2, 12, 34, 56, 0, time.UTC)),
+					ResourceVersion:   "12345",
+				},
+				Data: map[string][]byte{
+					"rails-env": []byte("production"),
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantOut: `NAME	TYPE	KEY		VALUE		LABELS			ANNOTATIONS	CREATED			RESOURCEVERSION
+rails	Opaque	rails-env	"production"	app=rails,role=web	<none>		2022-03-02T12:34:56Z	12345
+`,
+		},
+
+		"TLS secret": {
+			args: []string{"nginx"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nginx",
+				},
+				Data: map[string][]byte{
+					"tls.crt": tlsCert,
+					"tls.key": []byte("thisiskey"),
+				},
+				Type: v1.SecretTypeTLS,
+			},
+			wantOut: `NAME	TYPE			KEY	VALUE
+nginx	kubernetes.io/tls	tls.crt	subject="CN=example.com" sans=example.com,www.example.com issuer="CN=example.com" notAfter=2030-01-02T03:04:05Z
+nginx	kubernetes.io/tls	tls.key	"thisiskey"
+`,
+		},
+
+		"TLS secret with invalid certificate": {
+			args: []string{"nginx"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nginx",
+				},
+				Data: map[string][]byte{
+					"tls.crt": []byte("thisiscrt"),
+				},
+				Type: v1.SecretTypeTLS,
+			},
+			wantOut: `NAME	TYPE			KEY	VALUE
+nginx	kubernetes.io/tls	tls.crt	"thisiscrt"
+`,
+		},
+
+		"docker-registry secret": {
+			args: []string{"regcred"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "regcred",
+				},
+				Data: map[string][]byte{
+					".dockerconfigjson": []byte(`{"auths":{"quay.io":{"username":"robot","password":"token","auth":"cm9ib3Q6dG9rZW4="},"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`),
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+			},
+			wantOut: `NAME	TYPE				KEY			VALUE
+regcred	kubernetes.io/dockerconfigjson	.dockerconfigjson	server=ghcr.io username=dtan4 auth=********
+regcred	kubernetes.io/dockerconfigjson	.dockerconfigjson	server=quay.io username=robot auth=********
+`,
+		},
+
+		"one secret arg with -o json": {
+			output: "json",
+			args:   []string{"rails"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+					Labels: map[string]string{
+						"app": "rails",
+					},
+					CreationTimestamp: metav1.NewTime(time.Date(2022, 3, 2, 12, 34, 56, 0, time.UTC)),
+					ResourceVersion:   "12345",
+				},
+				Data: map[string][]byte{
+					"rails-env": []byte("production\n"),
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantOut: `[
+  {
+    "name": "rails",
+    "type": "Opaque",
+    "key": "rails-env",
+    "value": "production\n",
+    "labels": {
+      "app": "rails"
+    },
+    "creationTimestamp": "2022-03-02T12:34:56Z",
+    "resourceVersion": "12345"
+  }
+]
+`,
+		},
+
+		"no secret arg with -o yaml and --base64 option": {
+			base64encode: true,
+			output:       "yaml",
+			args:         []string{},
+			secrets: &v1.SecretList{
+				Items: []v1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "rails",
+						},
+						Data: map[string][]byte{
+							"rails-env": []byte("production"),
+						},
+						Type: v1.SecretTypeOpaque,
+					},
+				},
+			},
+			wantOut: `- creationTimestamp: null
+  key: rails-env
+  name: rails
+  resourceVersion: ""
+  type: Opaque
+  value: cHJvZHVjdGlvbg==
+`,
+		},
+
+		"binary value with -o yaml": {
+			output: "yaml",
+			args:   []string{"rails"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"logo.png":  {0x89, 0x50, 0x4e, 0x47},
+					"rails-env": []byte("production"),
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantOut: `- creationTimestamp: null
+  key: logo.png
+  name: rails
+  resourceVersion: ""
+  type: Opaque
+  value:
+    base64: iVBORw==
+- creationTimestamp: null
+  key: rails-env
+  name: rails
+  resourceVersion: ""
+  type: Opaque
+  value: production
+`,
+		},
+
+		"one secret arg with -o custom-columns": {
+			output: "custom-columns=KEY:.key,VALUE:.value,APP:.labels.app",
+			args:   []string{"rails"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"rails-env":    []byte("production"),
+					"database-url": []byte("postgres://example.com:5432/dbname"),
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantOut: `KEY		VALUE					APP
+database-url	"postgres://example.com:5432/dbname"	<none>
+rails-env	"production"				<none>
+`,
+		},
+
+		"one secret arg with -o go-template": {
+			output: "go-template={{.data.username}}:{{.data.password}}@{{.resourceVersion}}",
+			args:   []string{"postgres"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "postgres",
+					ResourceVersion: "12345",
+				},
+				Data: map[string][]byte{
+					"username": []byte("dtan4"),
+					"password": []byte("p@ssw0rd"),
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantOut: "dtan4:p@ssw0rd@12345",
+		},
+
+		"no secret arg with -o jsonpath": {
+			output: "jsonpath={.items[*].name}",
+			args:   []string{},
+			secrets: &v1.SecretList{
+				Items: []v1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "postgres",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "rails",
+						},
+					},
+				},
+			},
+			wantOut: "postgres rails",
+		},
+
+		"go-template with missing key": {
+			output: "go-template={{.data.foo}}",
+			args:   []string{"postgres"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "postgres",
+				},
+			},
+			wantErr: errors.New(`execute go-template: template: output:1:7: executing "output" at <.data.foo>: map has no entry for key "foo"`),
+		},
+
+		"unknown output format": {
+			output:  "toml",
+			args:    []string{"rails"},
+			wantErr: errors.New(`unknown output format "toml"`),
+		},
+
 		"one secret and error": {
 			args:    []string{"rails"},
 			err:     errors.New("cannot retrieve secret rails"),
@@ -131,6 +370,7 @@ rails	Opaque	rails-env	cHJvZHVjdGlvbg==
 
 			opts := listOpts{
 				base64encode: tc.base64encode,
+				output:       tc.output,
 			}
 			err := runList(context.Background(), k8sclient, namespace, tc.args, &out, &opts)
 
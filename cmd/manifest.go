@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestExtensions are the file extensions of Kubernetes manifests
+var manifestExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// isSecretManifest reports whether the first document in b is a manifest of Secret or List
+func isSecretManifest(b []byte) bool {
+	d := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+
+	for {
+		var tm metav1.TypeMeta
+
+		if err := d.Decode(&tm); err != nil {
+			return false
+		}
+
+		if tm.Kind == "" && tm.APIVersion == "" {
+			continue
+		}
+
+		return tm.APIVersion == "v1" && (tm.Kind == "Secret" || tm.Kind == "List")
+	}
+}
+
+// hasManifestExtension reports whether filename has the extension of Kubernetes manifests
+func hasManifestExtension(filename string) bool {
+	return manifestExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// parseSecretManifests parses multi-document YAML or JSON stream of Secret manifests. Items of List are also parsed.
+// stringData is merged into data, as the API server does.
+func parseSecretManifests(b []byte, source string) ([]*v1.Secret, error) {
+	d := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+
+	var secrets []*v1.Secret
+
+	for i := 1; ; i++ {
+		var raw json.RawMessage
+
+		if err := d.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("%s: parse document #%d: %w", source, i, err)
+		}
+
+		ss, err := decodeSecretManifest(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document #%d: %w", source, i, err)
+		}
+
+		secrets = append(secrets, ss...)
+	}
+
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("%s: no Secret manifest found", source)
+	}
+
+	return secrets, nil
+}
+
+func decodeSecretManifest(raw json.RawMessage) ([]*v1.Secret, error) {
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, nil
+	}
+
+	var tm metav1.TypeMeta
+
+	if err := json.Unmarshal(raw, &tm); err != nil {
+		return nil, err
+	}
+
+	if tm.APIVersion != "v1" {
+		return nil, fmt.Errorf("apiVersion must be v1, got %q", tm.APIVersion)
+	}
+
+	switch tm.Kind {
+	case "Secret":
+		var s v1.Secret
+
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+
+		if s.Name == "" {
+			return nil, errors.New("metadata.name must be specified")
+		}
+
+		if len(s.StringData) > 0 && s.Data == nil {
+			s.Data = make(map[string][]byte, len(s.StringData))
+		}
+
+		for k, v := range s.StringData {
+			s.Data[k] = []byte(v)
+		}
+
+		s.StringData = nil
+
+		return []*v1.Secret{&s}, nil
+	case "List":
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+
+		var secrets []*v1.Secret
+
+		for i, item := range list.Items {
+			ss, err := decodeSecretManifest(item)
+			if err != nil {
+				return nil, fmt.Errorf("item #%d: %w", i+1, err)
+			}
+
+			secrets = append(secrets, ss...)
+		}
+
+		return secrets, nil
+	default:
+		return nil, fmt.Errorf("only Secret can be loaded, got %s", tm.Kind)
+	}
+}
+
+// secretManifest is Secret manifest without empty metadata fields such as creationTimestamp
+type secretManifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name        string            `json:"name"`
+		Labels      map[string]string `json:"labels,omitempty"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	} `json:"metadata"`
+	Immutable *bool             `json:"immutable,omitempty"`
+	Type      v1.SecretType     `json:"type,omitempty"`
+	Data      map[string][]byte `json:"data,omitempty"`
+}
+
+// writeSecretManifests writes secrets as multi-document YAML, which can be applied with "kubectl apply".
+// Server-populated fields are stripped, and namespace is omitted so that they can be applied to any namespace.
+func writeSecretManifests(out io.Writer, secrets []*v1.Secret) error {
+	for i, secret := range secrets {
+		s := stripSecret(secret, secret.Name, []string{"*"}, []string{"*"})
+
+		m := secretManifest{
+			APIVersion: s.APIVersion,
+			Kind:       s.Kind,
+			Immutable:  s.Immutable,
+			Type:       s.Type,
+			Data:       s.Data,
+		}
+		m.Metadata.Name = s.Name
+		m.Metadata.Labels = s.Labels
+		m.Metadata.Annotations = s.Annotations
+
+		b, err := yaml.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("encode secret %q: %w", s.Name, err)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSecretManifestRoundTrip(t *testing.T) {
+	immutable := true
+
+	secrets := []*v1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rails",
+				Labels: map[string]string{
+					"app": "rails",
+				},
+			},
+			Immutable: &immutable,
+			Type:      v1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				"logo.png":  {0x89, 'P', 'N', 'G', 0x00, 0xff},
+				"rails-env": []byte("production\n"),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "empty",
+			},
+			Type: v1.SecretTypeOpaque,
+		},
+	}
+
+	var buf bytes.Buffer
+
+	if err := writeSecretManifests(&buf, secrets); err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	got, err := parseSecretManifests(buf.Bytes(), "secrets.yaml")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	for _, s := range got {
+		s.TypeMeta = metav1.TypeMeta{}
+	}
+
+	if !reflect.DeepEqual(got, secrets) {
+		t.Fatalf("want %#v, got %#v", secrets, got)
+	}
+}
+
+func TestParseSecretManifests(t *testing.T) {
+	testcases := map[string]struct {
+		content string
+		wantErr error
+	}{
+		"empty": {
+			content: "---\n",
+			wantErr: errors.New("secrets.yaml: no Secret manifest found"),
+		},
+		"without name": {
+			content: "apiVersion: v1\nkind: Secret\n",
+			wantErr: errors.New("secrets.yaml: document #1: metadata.name must be specified"),
+		},
+		"other apiVersion": {
+			content: "apiVersion: v2\nkind: Secret\n",
+			wantErr: errors.New(`secrets.yaml: document #1: apiVersion must be v1, got "v2"`),
+		},
+		"other kind in List": {
+			content: "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: ConfigMap\n",
+			wantErr: errors.New("secrets.yaml: document #1: item #1: only Secret can be loaded, got ConfigMap"),
+		},
+		"invalid base64": {
+			content: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: rails\ndata:\n  foo: '!!!'\n",
+			wantErr: errors.New("secrets.yaml: document #1: json: cannot unmarshal string into Go struct field Secret.data.foo of type []uint8: illegal base64 data at input byte 0"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseSecretManifests([]byte(tc.content), "secrets.yaml")
+
+			if err == nil {
+				t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+			}
+
+			if err.Error() != tc.wantErr.Error() {
+				t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+			}
+		})
+	}
+}
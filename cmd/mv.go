@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type mvOpts struct {
+	overwrite bool
+	update    updateOpts
+}
+
+func newMvCmd(out io.Writer) *cobra.Command {
+	opts := mvOpts{}
+
+	mvCmd := &cobra.Command{
+		Use:   "mv NAME OLDKEY NEWKEY",
+		Short: "Rename key in secret",
+		Long: `Rename key in secret
+
+The key is renamed with a single update, so the secret never has both or neither of the keys:
+
+$ k8sec mv rails database-url DATABASE_URL
+rails
+
+NEWKEY must not exist in the secret unless --overwrite is given.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 {
+				return errors.New("secret name, old key and new key must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runMv(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	mvCmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Overwrite NEWKEY if it exists")
+	addUpdateFlags(mvCmd.Flags(), &opts.update)
+
+	return mvCmd
+}
+
+func runMv(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *mvOpts) error {
+	name, oldKey, newKey := args[0], args[1], args[2]
+
+	if oldKey == newKey {
+		return fmt.Errorf("the key %s is renamed to itself", oldKey)
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get current secret %q: %w", name, err)
+	}
+
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		v, ok := s.Data[oldKey]
+		if !ok {
+			return fmt.Errorf("the key %s does not exist", oldKey)
+		}
+
+		if _, ok := s.Data[newKey]; ok && !opts.overwrite {
+			return fmt.Errorf("the key %s already exists, use --overwrite to replace it", newKey)
+		}
+
+		s.Data[newKey] = v
+		delete(s.Data, oldKey)
+
+		// Expiry is checked only for the certificate moved to tls.crt, as set does
+		var now time.Time
+		if newKey == v1.TLSCertKey {
+			now = time.Now()
+		}
+
+		return secrettype.Validate(s, now)
+	})
+	if err != nil {
+		return fmt.Errorf("rename key in secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, s.Name)
+	}
+
+	return nil
+}
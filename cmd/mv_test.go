@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunMv(t *testing.T) {
+	testcases := map[string]struct {
+		args []string
+		opts mvOpts
+		// secret is used instead of the Opaque secret "rails" if set
+		secret   *v1.Secret
+		wantData map[string][]byte
+		wantOut  string
+		wantErr  error
+	}{
+		"rename key": {
+			args: []string{"rails", "database-url", "DATABASE_URL"},
+			wantData: map[string][]byte{
+				"DATABASE_URL": []byte("postgres://example.com:5432/dbname"),
+				"rails-env":    []byte("production"),
+			},
+			wantOut: "rails\n",
+		},
+
+		"overwrite existing key": {
+			args: []string{"rails", "database-url", "rails-env"},
+			opts: mvOpts{
+				overwrite: true,
+			},
+			wantData: map[string][]byte{
+				"rails-env": []byte("postgres://example.com:5432/dbname"),
+			},
+			wantOut: "rails\n",
+		},
+
+		"existing key without --overwrite": {
+			args:    []string{"rails", "database-url", "rails-env"},
+			wantErr: errors.New(`rename key in secret "rails": the key rails-env already exists, use --overwrite to replace it`),
+		},
+
+		"missing key": {
+			args:    []string{"rails", "foo", "bar"},
+			wantErr: errors.New(`rename key in secret "rails": the key foo does not exist`),
+		},
+
+		"invalidate typed secret": {
+			args: []string{"postgres", "password", "pass"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "postgres",
+				},
+				Type: v1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					v1.BasicAuthUsernameKey: []byte("dtan4"),
+					v1.BasicAuthPasswordKey: []byte("p@ssw0rd"),
+				},
+			},
+			wantErr: errors.New(`rename key in secret "postgres": secret of type kubernetes.io/basic-auth must have key password`),
+		},
+
+		"same key": {
+			args:    []string{"rails", "foo", "foo"},
+			wantErr: errors.New("the key foo is renamed to itself"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			secret := tc.secret
+			if secret == nil {
+				secret = &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "rails",
+					},
+					Data: map[string][]byte{
+						"database-url": []byte("postgres://example.com:5432/dbname"),
+						"rails-env":    []byte("production"),
+					},
+				}
+			}
+
+			k8sclient := &fakeClient{
+				getSecretResponse: secret,
+			}
+
+			var out bytes.Buffer
+
+			err := runMv(context.Background(), k8sclient, namespace, tc.args, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				if k8sclient.updatedSecret != nil {
+					t.Fatalf("want no secret updated")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(k8sclient.updatedSecret.Data, tc.wantData) {
+					t.Fatalf("want data %#v, got %#v", tc.wantData, k8sclient.updatedSecret.Data)
+				}
+
+				if out.String() != tc.wantOut {
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable         = ""
+	outputWide          = "wide"
+	outputJSON          = "json"
+	outputYAML          = "yaml"
+	outputCustomColumns = "custom-columns"
+	outputGoTemplate    = "go-template"
+	outputJSONPath      = "jsonpath"
+)
+
+type outputFormat struct {
+	kind string
+	arg  string
+}
+
+// parseOutputFormat parses the value of -o/--output flag
+func parseOutputFormat(output string) (outputFormat, error) {
+	kind, arg, _ := strings.Cut(output, "=")
+
+	switch kind {
+	case outputTable, outputWide, outputJSON, outputYAML:
+		if arg != "" {
+			return outputFormat{}, fmt.Errorf("output format %q does not take any argument", kind)
+		}
+	case outputCustomColumns:
+		if arg == "" {
+			return outputFormat{}, errors.New("custom-columns format must be specified like custom-columns=NAME:.name,KEY:.key")
+		}
+	case outputGoTemplate, outputJSONPath:
+		if arg == "" {
+			return outputFormat{}, fmt.Errorf("template must be specified like %s=TEMPLATE", kind)
+		}
+	default:
+		return outputFormat{}, fmt.Errorf("unknown output format %q", output)
+	}
+
+	return outputFormat{
+		kind: kind,
+		arg:  arg,
+	}, nil
+}
+
+// tabular reports whether the format is printed as a table
+func (f outputFormat) tabular() bool {
+	return f.kind == outputTable || f.kind == outputWide || f.kind == outputCustomColumns
+}
+
+// template reports whether the format is rendered with user-given template
+func (f outputFormat) template() bool {
+	return f.kind == outputGoTemplate || f.kind == outputJSONPath
+}
+
+// secretView represents Kubernetes Secret with decoded data, used as the input of templates
+type secretView struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Type            string            `json:"type"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	ResourceVersion string            `json:"resourceVersion"`
+	Data            map[string]string `json:"data"`
+}
+
+// secretViewList represents the list of secretView
+type secretViewList struct {
+	Items []secretView `json:"items"`
+}
+
+func newSecretView(secret *v1.Secret, encode func([]byte) string) secretView {
+	data := make(map[string]string, len(secret.Data))
+
+	for key, value := range secret.Data {
+		data[key] = encode(value)
+	}
+
+	return secretView{
+		Name:            secret.Name,
+		Namespace:       secret.Namespace,
+		Type:            string(secret.Type),
+		Labels:          secret.Labels,
+		Annotations:     secret.Annotations,
+		ResourceVersion: secret.ResourceVersion,
+		Data:            data,
+	}
+}
+
+// printTemplate renders the given object with go-template or JSONPath template
+func printTemplate(out io.Writer, v interface{}, format outputFormat) error {
+	obj, err := toGenericObject(v)
+	if err != nil {
+		return err
+	}
+
+	switch format.kind {
+	case outputGoTemplate:
+		tmpl, err := template.New("output").Option("missingkey=error").Parse(format.arg)
+		if err != nil {
+			return fmt.Errorf("parse go-template: %w", err)
+		}
+
+		if err := tmpl.Execute(out, obj); err != nil {
+			return fmt.Errorf("execute go-template: %w", err)
+		}
+	case outputJSONPath:
+		p := jsonpath.New("output")
+		if err := p.Parse(format.arg); err != nil {
+			return fmt.Errorf("parse jsonpath: %w", err)
+		}
+
+		if err := p.Execute(out, obj); err != nil {
+			return fmt.Errorf("execute jsonpath: %w", err)
+		}
+	default:
+		return fmt.Errorf("output format %q is not a template", format.kind)
+	}
+
+	return nil
+}
+
+func printSecrets(out io.Writer, secrets []Secret, format outputFormat) error {
+	switch format.kind {
+	case outputTable:
+		return printSecretsTable(out, secrets, false)
+	case outputWide:
+		return printSecretsTable(out, secrets, true)
+	case outputJSON:
+		b, err := json.MarshalIndent(secrets, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode secrets as JSON: %w", err)
+		}
+
+		fmt.Fprintln(out, string(b))
+	case outputYAML:
+		b, err := yaml.Marshal(secrets)
+		if err != nil {
+			return fmt.Errorf("encode secrets as YAML: %w", err)
+		}
+
+		fmt.Fprint(out, string(b))
+	case outputCustomColumns:
+		return printSecretsCustomColumns(out, secrets, format.arg)
+	default:
+		return fmt.Errorf("unknown output format %q", format.kind)
+	}
+
+	return nil
+}
+
+func printSecretsTable(out io.Writer, secrets []Secret, wide bool) error {
+	w := new(tabwriter.Writer)
+	w.Init(out, 0, 8, 0, '\t', 0)
+
+	header := []string{"NAME", "TYPE", "KEY", "VALUE"}
+	if wide {
+		header = append(header, "LABELS", "ANNOTATIONS", "CREATED", "RESOURCEVERSION")
+	}
+
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, secret := range secrets {
+		columns := []string{secret.Name, secret.Type, secret.Key, fmt.Sprint(secret.Value)}
+		if wide {
+			columns = append(columns,
+				formatStringMap(secret.Labels),
+				formatStringMap(secret.Annotations),
+				formatTimestamp(secret.CreationTimestamp.Time),
+				secret.ResourceVersion,
+			)
+		}
+
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func printSecretsCustomColumns(out io.Writer, secrets []Secret, spec string) error {
+	var (
+		headers []string
+		parsers []*jsonpath.JSONPath
+	)
+
+	for _, column := range strings.Split(spec, ",") {
+		header, field, ok := strings.Cut(column, ":")
+		if !ok || header == "" || field == "" {
+			return fmt.Errorf("custom column must be in HEADER:FIELD format, got %q", column)
+		}
+
+		p := jsonpath.New(header).AllowMissingKeys(true)
+		if err := p.Parse(relaxedJSONPath(field)); err != nil {
+			return fmt.Errorf("parse custom column %q: %w", column, err)
+		}
+
+		headers = append(headers, header)
+		parsers = append(parsers, p)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(out, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, secret := range secrets {
+		obj, err := toGenericObject(secret)
+		if err != nil {
+			return err
+		}
+
+		columns := make([]string, 0, len(parsers))
+
+		for _, p := range parsers {
+			results, err := p.FindResults(obj)
+			if err != nil {
+				return fmt.Errorf("evaluate custom column: %w", err)
+			}
+
+			values := []string{}
+
+			for _, result := range results {
+				for _, v := range result {
+					values = append(values, fmt.Sprintf("%v", v.Interface()))
+				}
+			}
+
+			if len(values) == 0 {
+				columns = append(columns, "<none>")
+			} else {
+				columns = append(columns, strings.Join(values, ","))
+			}
+		}
+
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// relaxedJSONPath wraps the given JSONPath expression with braces as kubectl does
+func relaxedJSONPath(path string) string {
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+
+	return "{" + path + "}"
+}
+
+// toGenericObject converts the given value to the generic form decoded from JSON
+func toGenericObject(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode object as JSON: %w", err)
+	}
+
+	var obj interface{}
+
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, fmt.Errorf("decode object from JSON: %w", err)
+	}
+
+	return obj, nil
+}
+
+func formatStringMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+
+	kvs := make([]string, 0, len(m))
+
+	for k, v := range m {
+		kvs = append(kvs, k+"="+v)
+	}
+
+	sort.Strings(kvs)
+
+	return strings.Join(kvs, ",")
+}
+
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
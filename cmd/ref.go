@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// secretRef represents a reference to secret in [CONTEXT:][NAMESPACE/]NAME format
+type secretRef struct {
+	context   string
+	namespace string
+	name      string
+}
+
+// parseSecretRef parses secret reference. Context name can contain ":" and "/" (e.g. Amazon EKS cluster ARN) while
+// namespace and secret name cannot, so the reference is parsed from the right: the rightmost separator tells whether
+// NAMESPACE/ is given. Namespace must be a DNS-1123 label and name must be a DNS-1123 subdomain, so that a part of
+// context is never taken as namespace or name.
+func parseSecretRef(s string) (secretRef, error) {
+	ref := secretRef{}
+
+	rest, hasNamespace := s, false
+
+	if i := strings.LastIndex(rest, "/"); i > strings.LastIndex(rest, ":") {
+		ref.name, rest, hasNamespace = rest[i+1:], rest[:i], true
+	}
+
+	if j := strings.LastIndex(rest, ":"); j >= 0 {
+		ref.context, rest = rest[:j], rest[j+1:]
+
+		if ref.context == "" {
+			return secretRef{}, fmt.Errorf("invalid secret reference %q: context is empty", s)
+		}
+	}
+
+	if hasNamespace {
+		ref.namespace = rest
+
+		if ref.namespace == "" {
+			return secretRef{}, fmt.Errorf("invalid secret reference %q: namespace is empty", s)
+		}
+
+		if errs := validation.IsDNS1123Label(ref.namespace); len(errs) > 0 {
+			return secretRef{}, fmt.Errorf("invalid secret reference %q: invalid namespace %q: %s", s, ref.namespace, strings.Join(errs, ", "))
+		}
+	} else {
+		ref.name = rest
+	}
+
+	if ref.name == "" {
+		return secretRef{}, fmt.Errorf("invalid secret reference %q: name is empty", s)
+	}
+
+	if errs := validation.IsDNS1123Subdomain(ref.name); len(errs) > 0 {
+		return secretRef{}, fmt.Errorf("invalid secret reference %q: invalid name %q: %s", s, ref.name, strings.Join(errs, ", "))
+	}
+
+	return ref, nil
+}
+
+func (r secretRef) String() string {
+	s := r.name
+
+	if r.namespace != "" {
+		s = r.namespace + "/" + s
+	}
+
+	if r.context != "" {
+		s = r.context + ":" + s
+	}
+
+	return s
+}
+
+// clientFactory returns Kubernetes API client for the given context.
+// Empty context means the context given by --context flag, or the current context in kubeconfig.
+type clientFactory func(kubeContext string) (client.Client, error)
+
+// newClientFactory returns clientFactory which initializes one client per context.
+// Clients are wrapped with dryRunClient if --dry-run flag is given, which prints the secrets to out.
+func newClientFactory(out io.Writer) clientFactory {
+	clients := map[string]client.Client{}
+
+	return func(kubeContext string) (client.Client, error) {
+		switch rootOpts.dryRun {
+		case dryRunModeNone, dryRunModeClient, dryRunModeServer:
+		default:
+			return nil, fmt.Errorf("--dry-run must be one of %q, %q or %q, got %q", dryRunModeNone, dryRunModeClient, dryRunModeServer, rootOpts.dryRun)
+		}
+
+		if kubeContext == "" {
+			kubeContext = rootOpts.context
+		}
+
+		if c, ok := clients[kubeContext]; ok {
+			return c, nil
+		}
+
+		var c client.Client
+
+		c, err := client.New(rootOpts.kubeconfig, kubeContext, rootOpts.dryRun == dryRunModeServer)
+		if err != nil {
+			return nil, fmt.Errorf("initialize Kubernetes API client: %w", err)
+		}
+
+		if rootOpts.dryRun != dryRunModeNone {
+			c = &dryRunClient{
+				Client: c,
+				out:    out,
+				server: rootOpts.dryRun == dryRunModeServer,
+			}
+		}
+
+		clients[kubeContext] = c
+
+		return c, nil
+	}
+}
+
+// newClient returns Kubernetes API client for the context given by --context flag
+func newClient(out io.Writer) (client.Client, error) {
+	return newClientFactory(out)("")
+}
+
+// resolveSecretRef returns the client and namespace for the given secret reference.
+// namespace is used if the reference does not have namespace, and the default namespace of context is used if both
+// are empty.
+func resolveSecretRef(newClient clientFactory, ref secretRef, namespace string) (client.Client, string, error) {
+	k8sclient, err := newClient(ref.context)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case ref.namespace != "":
+		namespace = ref.namespace
+	case namespace == "":
+		namespace = k8sclient.DefaultNamespace()
+	}
+
+	return k8sclient, namespace, nil
+}
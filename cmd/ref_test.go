@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	testcases := map[string]struct {
+		s       string
+		want    secretRef
+		wantErr error
+	}{
+		"name only": {
+			s:    "rails",
+			want: secretRef{name: "rails"},
+		},
+		"namespace and name": {
+			s:    "staging/rails",
+			want: secretRef{namespace: "staging", name: "rails"},
+		},
+		"context and name": {
+			s:    "production:rails",
+			want: secretRef{context: "production", name: "rails"},
+		},
+		"context, namespace and name": {
+			s:    "production:app/rails",
+			want: secretRef{context: "production", namespace: "app", name: "rails"},
+		},
+		"context with colons and slashes": {
+			s:    "arn:aws:eks:ap-northeast-1:123456789012:cluster/production:app/rails",
+			want: secretRef{context: "arn:aws:eks:ap-northeast-1:123456789012:cluster/production", namespace: "app", name: "rails"},
+		},
+		"context with colons and slashes without namespace": {
+			s:    "arn:aws:eks:ap-northeast-1:123456789012:cluster/production:rails",
+			want: secretRef{context: "arn:aws:eks:ap-northeast-1:123456789012:cluster/production", name: "rails"},
+		},
+		"context with slash only": {
+			s:    "team/production:rails",
+			want: secretRef{context: "team/production", name: "rails"},
+		},
+		"name with dots": {
+			s:    "app/rails.v1",
+			want: secretRef{namespace: "app", name: "rails.v1"},
+		},
+		"invalid namespace": {
+			s:       "production:App/rails",
+			wantErr: errors.New(`invalid secret reference "production:App/rails": invalid namespace "App": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`),
+		},
+		"invalid name": {
+			s:       "app/Rails_secret",
+			wantErr: errors.New(`invalid secret reference "app/Rails_secret": invalid name "Rails_secret": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		"empty name": {
+			s:       "app/",
+			wantErr: errors.New(`invalid secret reference "app/": name is empty`),
+		},
+		"empty namespace": {
+			s:       "production:/rails",
+			wantErr: errors.New(`invalid secret reference "production:/rails": namespace is empty`),
+		},
+		"empty context": {
+			s:       ":rails",
+			wantErr: errors.New(`invalid secret reference ":rails": context is empty`),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSecretRef(tc.s)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if got != tc.want {
+					t.Fatalf("want %#v, got %#v", tc.want, got)
+				}
+
+				if got.String() != tc.s {
+					t.Fatalf("want %q, got %q", tc.s, got.String())
+				}
+			}
+		})
+	}
+}
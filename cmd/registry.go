@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/api/core/v1"
+)
+
+// registryCredentialOpts are the options to give the credentials of docker registry. Username and password of
+// basic-auth secret are given with them too.
+type registryCredentialOpts struct {
+	username      string
+	email         string
+	passwordStdin bool
+	// readPassword reads a value without echo after printing prompt
+	readPassword func(prompt string) ([]byte, error)
+}
+
+func newRegistryCmd(in io.Reader, out io.Writer) *cobra.Command {
+	registryCmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Manage registries in docker-registry secret",
+		Long: `Manage registries in docker-registry secret
+
+Credentials of a registry are added to or removed from .dockerconfigjson of the kubernetes.io/dockerconfigjson secret.
+The other registries are kept as they are.
+`,
+	}
+
+	registryCmd.AddCommand(newRegistryAddCmd(in, out))
+	registryCmd.AddCommand(newRegistryRemoveCmd(out))
+
+	return registryCmd
+}
+
+func newRegistryCredentialOpts(in io.Reader) registryCredentialOpts {
+	return registryCredentialOpts{
+		readPassword: func(prompt string) ([]byte, error) {
+			return readPassword(in, os.Stderr, prompt)
+		},
+	}
+}
+
+func addRegistryCredentialFlags(flags *pflag.FlagSet, opts *registryCredentialOpts) {
+	addCredentialFlags(flags, opts)
+	flags.StringVar(&opts.email, "email", "", "Email of the registry user")
+}
+
+// addCredentialFlags adds the flags of username and password, without email which only docker registry has
+func addCredentialFlags(flags *pflag.FlagSet, opts *registryCredentialOpts) {
+	flags.StringVar(&opts.username, "username", "", "Username")
+	flags.BoolVar(&opts.passwordStdin, "password-stdin", false, "Read the password from stdin. The password is read from interactive prompt if not specified")
+}
+
+// readCredential reads the password from stdin or prompt, and returns it with the username
+func readCredential(in io.Reader, opts *registryCredentialOpts) (string, []byte, error) {
+	if opts.username == "" {
+		return "", nil, errors.New("--username must be specified")
+	}
+
+	password, err := readPasswordInput(in, opts.passwordStdin, opts.readPassword)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return opts.username, password, nil
+}
+
+// readRegistryEntry reads the password from stdin or prompt, and returns the credentials of registry
+func readRegistryEntry(in io.Reader, opts *registryCredentialOpts) (secrettype.DockerConfigEntry, error) {
+	username, password, err := readCredential(in, opts)
+	if err != nil {
+		return secrettype.DockerConfigEntry{}, err
+	}
+
+	return secrettype.NewDockerConfigEntry(username, string(password), opts.email), nil
+}
+
+// parseDockerConfigSecret parses .dockerconfigjson of the kubernetes.io/dockerconfigjson secret
+func parseDockerConfigSecret(s *v1.Secret) (*secrettype.DockerConfig, error) {
+	if s.Type != v1.SecretTypeDockerConfigJson {
+		return nil, fmt.Errorf("secret %q is not of type %s", s.Name, v1.SecretTypeDockerConfigJson)
+	}
+
+	b, ok := s.Data[v1.DockerConfigJsonKey]
+	if !ok {
+		return secrettype.NewDockerConfig(), nil
+	}
+
+	return secrettype.ParseDockerConfig(b)
+}
+
+// setDockerConfig writes the config to .dockerconfigjson of the secret
+func setDockerConfig(s *v1.Secret, c *secrettype.DockerConfig) error {
+	b, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if s.Data == nil {
+		s.Data = map[string][]byte{}
+	}
+
+	s.Data[v1.DockerConfigJsonKey] = b
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type registryAddOpts struct {
+	credential registryCredentialOpts
+	update     updateOpts
+}
+
+func newRegistryAddCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := registryAddOpts{
+		credential: newRegistryCredentialOpts(in),
+	}
+
+	registryAddCmd := &cobra.Command{
+		Use:   "add NAME SERVER --username USERNAME [--password-stdin]",
+		Short: "Add registry to docker-registry secret",
+		Long: `Add registry to docker-registry secret
+
+The credentials of the registry are replaced if it already exists in the secret. The password is read from stdin with
+--password-stdin, otherwise from interactive prompt:
+
+$ echo "$GITHUB_TOKEN" | k8sec registry add regcred ghcr.io --username dtan4 --password-stdin
+regcred
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("secret name and registry server must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runRegistryAdd(ctx, k8sclient, namespace, args, in, out, &opts)
+		},
+	}
+
+	addRegistryCredentialFlags(registryAddCmd.Flags(), &opts.credential)
+	addUpdateFlags(registryAddCmd.Flags(), &opts.update)
+
+	return registryAddCmd
+}
+
+func runRegistryAdd(ctx context.Context, k8sclient client.Client, namespace string, args []string, in io.Reader, out io.Writer, opts *registryAddOpts) error {
+	name, server := args[0], args[1]
+
+	entry, err := readRegistryEntry(in, &opts.credential)
+	if err != nil {
+		return err
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get current secret %q: %w", name, err)
+	}
+
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		c, err := parseDockerConfigSecret(s)
+		if err != nil {
+			return err
+		}
+
+		if err := c.Set(server, entry); err != nil {
+			return err
+		}
+
+		return setDockerConfig(s, c)
+	})
+	if err != nil {
+		return fmt.Errorf("add registry to secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunRegistryAdd(t *testing.T) {
+	testcases := map[string]struct {
+		server     string
+		secret     *v1.Secret
+		wantConfig string
+		wantOut    string
+		wantErr    error
+	}{
+		"add registry": {
+			server: "quay.io",
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "regcred",
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					".dockerconfigjson": []byte(`{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`),
+				},
+			},
+			wantConfig: `{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="},"quay.io":{"username":"robot","password":"token","auth":"cm9ib3Q6dG9rZW4="}}}`,
+			wantOut:    "regcred\n",
+		},
+		"replace registry": {
+			server: "ghcr.io",
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "regcred",
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					".dockerconfigjson": []byte(`{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`),
+				},
+			},
+			wantConfig: `{"auths":{"ghcr.io":{"username":"robot","password":"token","auth":"cm9ib3Q6dG9rZW4="}}}`,
+			wantOut:    "regcred\n",
+		},
+		"not docker-registry secret": {
+			server: "quay.io",
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Type: v1.SecretTypeOpaque,
+			},
+			wantErr: errors.New(`add registry to secret "rails": secret "rails" is not of type kubernetes.io/dockerconfigjson`),
+		},
+		"invalid docker config": {
+			server: "quay.io",
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "regcred",
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					".dockerconfigjson": []byte(`{}`),
+				},
+			},
+			wantErr: errors.New(`add registry to secret "regcred": parse docker config: auths does not exist`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: tc.secret,
+			}
+
+			opts := &registryAddOpts{
+				credential: registryCredentialOpts{
+					username:      "robot",
+					passwordStdin: true,
+				},
+			}
+
+			var out bytes.Buffer
+
+			err := runRegistryAdd(context.Background(), k8sclient, namespace, []string{tc.secret.Name, tc.server}, strings.NewReader("token\n"), &out, opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if got := string(k8sclient.updatedSecret.Data[".dockerconfigjson"]); got != tc.wantConfig {
+				t.Errorf("want %s, got %s", tc.wantConfig, got)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
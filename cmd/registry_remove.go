@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+type registryRemoveOpts struct {
+	update updateOpts
+}
+
+func newRegistryRemoveCmd(out io.Writer) *cobra.Command {
+	opts := registryRemoveOpts{}
+
+	registryRemoveCmd := &cobra.Command{
+		Use:   "remove NAME SERVER",
+		Short: "Remove registry from docker-registry secret",
+		Long: `Remove registry from docker-registry secret
+
+$ k8sec registry remove regcred ghcr.io
+regcred
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("secret name and registry server must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runRegistryRemove(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	addUpdateFlags(registryRemoveCmd.Flags(), &opts.update)
+
+	return registryRemoveCmd
+}
+
+func runRegistryRemove(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *registryRemoveOpts) error {
+	name, server := args[0], args[1]
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get current secret %q: %w", name, err)
+	}
+
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		c, err := parseDockerConfigSecret(s)
+		if err != nil {
+			return err
+		}
+
+		if !c.Remove(server) {
+			return fmt.Errorf("registry %s does not exist", server)
+		}
+
+		return setDockerConfig(s, c)
+	})
+	if err != nil {
+		return fmt.Errorf("remove registry from secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
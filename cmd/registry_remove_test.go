@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunRegistryRemove(t *testing.T) {
+	testcases := map[string]struct {
+		server     string
+		wantConfig string
+		wantOut    string
+		wantErr    error
+	}{
+		"remove registry": {
+			server:     "ghcr.io",
+			wantConfig: `{"auths":{"quay.io":{"auth":"cm9ib3Q6dG9rZW4="}},"credsStore":"desktop"}`,
+			wantOut:    "regcred\n",
+		},
+		"registry does not exist": {
+			server:  "docker.io",
+			wantErr: errors.New(`remove registry from secret "regcred": registry docker.io does not exist`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "regcred",
+					},
+					Type: v1.SecretTypeDockerConfigJson,
+					Data: map[string][]byte{
+						".dockerconfigjson": []byte(`{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="},"quay.io":{"auth":"cm9ib3Q6dG9rZW4="}},"credsStore":"desktop"}`),
+					},
+				},
+			}
+
+			var out bytes.Buffer
+
+			err := runRegistryRemove(context.Background(), k8sclient, namespace, []string{"regcred", tc.server}, &out, &registryRemoveOpts{})
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if got := string(k8sclient.updatedSecret.Data[".dockerconfigjson"]); got != tc.wantConfig {
+				t.Errorf("want %s, got %s", tc.wantConfig, got)
+			}
+
+			if out.String() != tc.wantOut {
+				t.Errorf("want %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
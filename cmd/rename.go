@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRenameCmd(out io.Writer) *cobra.Command {
+	renameCmd := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename secret",
+		Long: `Rename secret
+
+A new secret is created with the same type, data, labels and annotations, and then the old secret is deleted.
+If the old secret cannot be deleted, e.g. because it has been modified during the rename, the new secret is deleted so
+that the rename is rolled back:
+
+$ k8sec rename rails rails-production
+secret "rails" renamed to "rails-production"
+
+Server-populated metadata such as uid and ownerReferences are not kept.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("old and new secret names must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runRename(ctx, k8sclient, namespace, args, out)
+		},
+	}
+
+	return renameCmd
+}
+
+func runRename(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer) error {
+	oldName, newName := args[0], args[1]
+
+	if oldName == newName {
+		return fmt.Errorf("secret %q is renamed to itself", oldName)
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, oldName)
+	if err != nil {
+		return fmt.Errorf("get secret %q: %w", oldName, err)
+	}
+
+	if _, err := k8sclient.CreateSecret(ctx, namespace, stripSecret(s, newName, []string{"*"}, []string{"*"})); err != nil {
+		return fmt.Errorf("create secret %q: %w", newName, err)
+	}
+
+	// The old secret is deleted only if it has not been modified since it was got, so that no change is lost
+	deleteOptions := client.DeleteOptions{
+		Preconditions: &metav1.Preconditions{
+			UID:             &s.UID,
+			ResourceVersion: &s.ResourceVersion,
+		},
+	}
+
+	if err := k8sclient.DeleteSecret(ctx, namespace, oldName, deleteOptions); err != nil {
+		if rerr := k8sclient.DeleteSecret(ctx, namespace, newName, client.DeleteOptions{}); rerr != nil {
+			return fmt.Errorf("delete secret %q: %w (rollback also failed, delete secret %q manually: %v)", oldName, err, newName, rerr)
+		}
+
+		return fmt.Errorf("delete secret %q: %w (secret %q has been deleted to roll back)", oldName, err, newName)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintf(out, "secret %q renamed to %q\n", oldName, newName)
+	}
+
+	return nil
+}
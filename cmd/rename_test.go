@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRunRename(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "rails",
+			UID:             "d6b5f6c2",
+			ResourceVersion: "12345",
+			Labels: map[string]string{
+				"app": "rails",
+			},
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"database-url": []byte("postgres://example.com:5432/dbname"),
+		},
+	}
+
+	uid := types.UID("d6b5f6c2")
+	resourceVersion := "12345"
+
+	testcases := map[string]struct {
+		args             []string
+		dryRun           bool
+		err              error
+		deleteSecretErrs map[string]error
+		wantCreated      *v1.Secret
+		wantDeleted      []string
+		wantOptions      client.DeleteOptions
+		wantOut          string
+		wantErr          error
+	}{
+		"rename secret": {
+			args: []string{"rails", "rails-production"},
+			wantCreated: &v1.Secret{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Secret",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails-production",
+					Labels: map[string]string{
+						"app": "rails",
+					},
+				},
+				Type: v1.SecretTypeOpaque,
+				Data: map[string][]byte{
+					"database-url": []byte("postgres://example.com:5432/dbname"),
+				},
+			},
+			wantDeleted: []string{"rails"},
+			wantOptions: client.DeleteOptions{
+				Preconditions: &metav1.Preconditions{
+					UID:             &uid,
+					ResourceVersion: &resourceVersion,
+				},
+			},
+			wantOut: `secret "rails" renamed to "rails-production"
+`,
+		},
+
+		"dry run": {
+			args:   []string{"rails", "rails-production"},
+			dryRun: true,
+			wantOut: `secret "rails-production" in namespace "test" would be created (client dry run)
+NAME		TYPE	KEY		VALUE
+rails-productionOpaque	database-url	********
+secret "rails" in namespace "test" would be deleted (client dry run)
+`,
+		},
+
+		"rollback": {
+			args: []string{"rails", "rails-production"},
+			deleteSecretErrs: map[string]error{
+				"rails": errors.New("forbidden"),
+			},
+			wantDeleted: []string{"rails-production"},
+			wantErr:     errors.New(`delete secret "rails": forbidden (secret "rails-production" has been deleted to roll back)`),
+		},
+
+		"modified during rename": {
+			args: []string{"rails", "rails-production"},
+			deleteSecretErrs: map[string]error{
+				"rails": apierrors.NewConflict(v1.Resource("secrets"), "rails", errors.New("the ResourceVersion in the precondition (12345) does not match the ResourceVersion in record (12346)")),
+			},
+			wantDeleted: []string{"rails-production"},
+			wantErr:     errors.New(`delete secret "rails": Operation cannot be fulfilled on secrets "rails": the ResourceVersion in the precondition (12345) does not match the ResourceVersion in record (12346) (secret "rails-production" has been deleted to roll back)`),
+		},
+
+		"rollback failure": {
+			args: []string{"rails", "rails-production"},
+			deleteSecretErrs: map[string]error{
+				"rails":            errors.New("forbidden"),
+				"rails-production": errors.New("timeout"),
+			},
+			wantErr: errors.New(`delete secret "rails": forbidden (rollback also failed, delete secret "rails-production" manually: timeout)`),
+		},
+
+		"same name": {
+			args:    []string{"rails", "rails"},
+			wantErr: errors.New(`secret "rails" is renamed to itself`),
+		},
+
+		"error at get secret": {
+			args:    []string{"rails", "rails-production"},
+			err:     errors.New("cannot get secret"),
+			wantErr: errors.New(`get secret "rails": cannot get secret`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: secret,
+				err:               tc.err,
+				deleteSecretErrs:  tc.deleteSecretErrs,
+			}
+
+			var (
+				out bytes.Buffer
+				c   client.Client = k8sclient
+			)
+
+			if tc.dryRun {
+				c = &dryRunClient{
+					Client: k8sclient,
+					out:    &out,
+				}
+			}
+
+			err := runRename(context.Background(), c, namespace, tc.args, &out)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(k8sclient.createdSecret, tc.wantCreated) {
+					t.Fatalf("want created %#v, got %#v", tc.wantCreated, k8sclient.createdSecret)
+				}
+
+				if !reflect.DeepEqual(k8sclient.deleteOptions, tc.wantOptions) {
+					t.Fatalf("want delete options %#v, got %#v", tc.wantOptions, k8sclient.deleteOptions)
+				}
+
+				if out.String() != tc.wantOut {
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+
+			if !reflect.DeepEqual(k8sclient.deletedSecrets, tc.wantDeleted) {
+				t.Fatalf("want deleted %v, got %v", tc.wantDeleted, k8sclient.deletedSecrets)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -12,6 +13,7 @@ var rootOpts = struct {
 	context    string
 	kubeconfig string
 	namespace  string
+	dryRun     string
 }{}
 
 func newRootCmd(in io.Reader, out io.Writer, args []string) *cobra.Command {
@@ -30,11 +32,25 @@ func newRootCmd(in io.Reader, out io.Writer, args []string) *cobra.Command {
 	flags.StringVar(&rootOpts.context, "context", "", "Kubernetes context")
 	flags.StringVar(&rootOpts.kubeconfig, "kubeconfig", "", "Path of kubeconfig")
 	flags.StringVarP(&rootOpts.namespace, "namespace", "n", "", "Kubernetes namespace")
+	flags.StringVar(&rootOpts.dryRun, "dry-run", dryRunModeNone, `Print the secrets to be written with masked values instead of writing them. One of: "none", "client" (no write request is sent) or "server" (write requests are sent as server-side dry run)`)
 
+	cmd.AddCommand(newCopyCmd(out))
+	cmd.AddCommand(newCreateCmd(in, out))
+	cmd.AddCommand(newDeleteCmd(in, out))
+	cmd.AddCommand(newDiffCmd(in, out))
 	cmd.AddCommand(newDumpCmd(out))
+	cmd.AddCommand(newEditCmd(out))
+	cmd.AddCommand(newExecFilesCmd(in, out))
+	cmd.AddCommand(newGenerateCmd(out))
+	cmd.AddCommand(newGetCmd(out))
 	cmd.AddCommand(newListCmd(out))
 	cmd.AddCommand(newLoadCmd(in, out))
-	cmd.AddCommand(newSetCmd(out))
+	cmd.AddCommand(newMvCmd(out))
+	cmd.AddCommand(newRegistryCmd(in, out))
+	cmd.AddCommand(newRenameCmd(out))
+	cmd.AddCommand(newRotateCmd(out))
+	cmd.AddCommand(newRunCmd(in, out))
+	cmd.AddCommand(newSetCmd(in, out))
 	cmd.AddCommand(newUnsetCmd(out))
 	cmd.AddCommand(newVersionCmd(out))
 
@@ -47,7 +63,21 @@ func Execute(in io.Reader, out io.Writer, args []string) {
 	cmd := newRootCmd(in, out, args)
 
 	if err := cmd.Execute(); err != nil {
+		var eerr *exitError
+		if errors.As(err, &eerr) {
+			os.Exit(eerr.code)
+		}
+
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
+
+// exitError makes the process exit with the given status code without printing any error message
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRootCmdDryRunFlag(t *testing.T) {
+	testcases := map[string]struct {
+		args       []string
+		wantDryRun string
+		wantArgs   []string
+	}{
+		"not given": {
+			args:       []string{"set", "rails", "rails-env=staging"},
+			wantDryRun: dryRunModeNone,
+			wantArgs:   []string{"rails", "rails-env=staging"},
+		},
+
+		"with equal sign": {
+			args:       []string{"set", "--dry-run=server", "rails", "rails-env=staging"},
+			wantDryRun: dryRunModeServer,
+			wantArgs:   []string{"rails", "rails-env=staging"},
+		},
+
+		"separated by space": {
+			args:       []string{"set", "--dry-run", "server", "rails", "rails-env=staging"},
+			wantDryRun: dryRunModeServer,
+			wantArgs:   []string{"rails", "rails-env=staging"},
+		},
+
+		"without value takes next argument as mode": {
+			args:       []string{"set", "--dry-run", "rails", "rails-env=staging"},
+			wantDryRun: "rails",
+			wantArgs:   []string{"rails-env=staging"},
+		},
+	}
+
+	defer func(dryRun string) {
+		rootOpts.dryRun = dryRun
+	}(rootOpts.dryRun)
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			rootCmd := newRootCmd(strings.NewReader(""), &out, tc.args)
+
+			c, args, err := rootCmd.Find(tc.args)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if err := c.ParseFlags(args); err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if rootOpts.dryRun != tc.wantDryRun {
+				t.Errorf("want --dry-run %q, got %q", tc.wantDryRun, rootOpts.dryRun)
+			}
+
+			if got := c.Flags().Args(); !reflect.DeepEqual(got, tc.wantArgs) {
+				t.Errorf("want args %q, got %q", tc.wantArgs, got)
+			}
+		})
+	}
+}
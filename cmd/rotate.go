@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+const defaultPreviousSuffix = "_PREVIOUS"
+
+type rotateOpts struct {
+	keepPrevious   bool
+	previousSuffix string
+	update         updateOpts
+}
+
+func newRotateCmd(out io.Writer) *cobra.Command {
+	opts := rotateOpts{}
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate NAME KEY1[:SPEC1] [KEY2[:SPEC2] ...]",
+		Short: "Replace existing values with random values generated securely",
+		Long: `Replace existing values with random values generated securely
+
+Values are generated in the same way as "k8sec generate", see "k8sec generate --help" for SPEC. The keys must exist:
+
+$ k8sec rotate rails secret-key-base:hex,64
+rails
+
+Keep the previous values for dual-credential rollover. With --keep-previous, the previous value of KEY is stored in
+KEY_PREVIOUS, or KEY + the suffix given by --previous-suffix:
+
+$ k8sec rotate --keep-previous rails database-password
+rails
+$ k8sec list rails
+NAME    TYPE    KEY                         VALUE
+rails   Opaque  database-password           "9Xk2..."
+rails   Opaque  database-password_PREVIOUS  "aB3d..."
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("too few arguments")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runRotate(ctx, k8sclient, namespace, args, out, &opts)
+		},
+	}
+
+	rotateCmd.Flags().BoolVar(&opts.keepPrevious, "keep-previous", false, "Keep the previous values in KEY_PREVIOUS")
+	rotateCmd.Flags().StringVar(&opts.previousSuffix, "previous-suffix", defaultPreviousSuffix, "Suffix of the keys to keep the previous values")
+	addUpdateFlags(rotateCmd.Flags(), &opts.update)
+
+	return rotateCmd
+}
+
+func runRotate(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *rotateOpts) error {
+	name := args[0]
+
+	if opts.keepPrevious && opts.previousSuffix == "" {
+		return fmt.Errorf("--previous-suffix must not be empty")
+	}
+
+	keys, data, err := generateValues(args[1:])
+	if err != nil {
+		return err
+	}
+
+	s, err := k8sclient.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get current secret %q: %w", name, err)
+	}
+
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		for _, k := range keys {
+			old, ok := s.Data[k]
+			if !ok {
+				return fmt.Errorf("the key %s does not exist", k)
+			}
+
+			if opts.keepPrevious {
+				s.Data[k+opts.previousSuffix] = old
+			}
+
+			s.Data[k] = data[k]
+		}
+
+		// Random values never make valid certificates, so expiry is not checked
+		return secrettype.Validate(s, time.Time{})
+	})
+	if err != nil {
+		return fmt.Errorf("rotate secret %q: %w", name, err)
+	}
+
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunRotate(t *testing.T) {
+	testcases := map[string]struct {
+		args []string
+		opts rotateOpts
+		// secret is used instead of the Opaque secret "rails" if set
+		secret *v1.Secret
+		// wantData has patterns of values
+		wantData map[string]string
+		wantErr  error
+	}{
+		"rotate": {
+			args: []string{"rails", "password:hex,8"},
+			wantData: map[string]string{
+				"password":  `^[0-9a-f]{16}$`,
+				"rails-env": `^production$`,
+			},
+		},
+
+		"keep previous value": {
+			args: []string{"rails", "password"},
+			opts: rotateOpts{
+				keepPrevious:   true,
+				previousSuffix: defaultPreviousSuffix,
+			},
+			wantData: map[string]string{
+				"password":          `^[a-zA-Z0-9]{32}$`,
+				"password_PREVIOUS": `^old-password$`,
+				"rails-env":         `^production$`,
+			},
+		},
+
+		"missing key": {
+			args:    []string{"rails", "foo"},
+			wantErr: errors.New(`rotate secret "rails": the key foo does not exist`),
+		},
+
+		"invalidate typed secret": {
+			args: []string{"github", "ssh-privatekey"},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "github",
+				},
+				Type: v1.SecretTypeSSHAuth,
+				Data: map[string][]byte{
+					v1.SSHAuthPrivateKey: []byte("old-private-key"),
+				},
+			},
+			wantErr: errors.New(`rotate secret "github": invalid SSH private key: ssh: no key found`),
+		},
+
+		"empty suffix": {
+			args: []string{"rails", "password"},
+			opts: rotateOpts{
+				keepPrevious: true,
+			},
+			wantErr: errors.New("--previous-suffix must not be empty"),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			secret := tc.secret
+			if secret == nil {
+				secret = &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "rails",
+					},
+					Data: map[string][]byte{
+						"password":  []byte("old-password"),
+						"rails-env": []byte("production"),
+					},
+				}
+			}
+
+			k8sclient := &fakeClient{
+				getSecretResponse: secret,
+			}
+
+			var out bytes.Buffer
+
+			err := runRotate(context.Background(), k8sclient, namespace, tc.args, &out, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			data := k8sclient.updatedSecret.Data
+
+			if len(data) != len(tc.wantData) {
+				t.Fatalf("want %d keys, got %d", len(tc.wantData), len(data))
+			}
+
+			for k, pattern := range tc.wantData {
+				if !regexp.MustCompile(pattern).Match(data[k]) {
+					t.Errorf("want value of %s to match %s, got %q", k, pattern, data[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+type runOpts struct {
+	prefix   string
+	only     []string
+	exclude  []string
+	sanitize bool
+}
+
+func newRunCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := runOpts{}
+
+	runCmd := &cobra.Command{
+		Use:   "run NAME [NAME ...] -- COMMAND [ARGS ...]",
+		Short: "Run command with secrets as environment variables",
+		Long: `Run command with secrets as environment variables
+
+Keys of the secrets are added to the environment of the command, without writing them to disk. When the same key
+exists in multiple secrets, the latter one is used:
+
+$ k8sec run rails -- bundle exec rails server
+$ k8sec run rails postgres -- env
+
+Key names can be converted to valid environment variable names with --sanitize (e.g. database-url to DATABASE_URL),
+and prefixed with --prefix:
+
+$ k8sec run --sanitize --prefix APP_ rails -- printenv APP_DATABASE_URL
+postgres://example.com:5432/dbname
+
+Add the specified keys only, or the keys except the specified ones:
+
+$ k8sec run --only database-url,rails-env rails -- bundle exec rails console
+$ k8sec run --exclude private-key rails -- bundle exec rails console
+
+The exit status of the command is passed through.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return errors.New("command must be specified after --")
+			}
+
+			if dash == 0 {
+				return errors.New("secret name must be specified")
+			}
+
+			ctx := context.Background()
+
+			k8sclient, err := newClient(out)
+			if err != nil {
+				return err
+			}
+
+			var namespace string
+
+			if rootOpts.namespace != "" {
+				namespace = rootOpts.namespace
+			} else {
+				namespace = k8sclient.DefaultNamespace()
+			}
+
+			return runRun(ctx, k8sclient, namespace, args[:dash], args[dash:], in, out, &opts)
+		},
+	}
+
+	runCmd.Flags().StringVar(&opts.prefix, "prefix", "", "Prefix added to environment variable names")
+	runCmd.Flags().StringSliceVar(&opts.only, "only", []string{}, "Keys to add to the environment. All keys are added if not specified")
+	runCmd.Flags().StringSliceVar(&opts.exclude, "exclude", []string{}, "Keys not to add to the environment")
+	runCmd.Flags().BoolVar(&opts.sanitize, "sanitize", false, "Convert keys to valid environment variable names (e.g. database-url to DATABASE_URL)")
+
+	return runCmd
+}
+
+func runRun(ctx context.Context, k8sclient client.Client, namespace string, names, command []string, in io.Reader, out io.Writer, opts *runOpts) error {
+	env, err := secretEnv(ctx, k8sclient, namespace, names, opts)
+	if err != nil {
+		return err
+	}
+
+	return execCommand(command, append(os.Environ(), env...), in, out)
+}
+
+// secretEnv returns the keys of secrets in KEY=VALUE format, to be added to the environment of command
+func secretEnv(ctx context.Context, k8sclient client.Client, namespace string, names []string, opts *runOpts) ([]string, error) {
+	only := map[string]bool{}
+	for _, k := range opts.only {
+		only[k] = true
+	}
+
+	exclude := map[string]bool{}
+	for _, k := range opts.exclude {
+		exclude[k] = true
+	}
+
+	vars := map[string]string{}
+	found := map[string]bool{}
+
+	for _, name := range names {
+		s, err := k8sclient.GetSecret(ctx, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("get secret %q: %w", name, err)
+		}
+
+		// source keys of environment variables in this secret, to detect collisions by sanitization
+		sources := map[string]string{}
+
+		keys := make([]string, 0, len(s.Data))
+		for k := range s.Data {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if len(only) > 0 && !only[k] || exclude[k] {
+				continue
+			}
+
+			found[k] = true
+
+			v := s.Data[k]
+			if strings.IndexByte(string(v), 0) >= 0 {
+				return nil, fmt.Errorf("value of key %s in secret %q contains NUL character, which cannot be used in environment variable", k, name)
+			}
+
+			envName := opts.prefix + k
+			if opts.sanitize {
+				envName = sanitizeEnvName(envName)
+			}
+
+			if src, ok := sources[envName]; ok {
+				return nil, fmt.Errorf("keys %s and %s in secret %q are both converted to %s", src, k, name, envName)
+			}
+
+			sources[envName] = k
+			vars[envName] = string(v)
+		}
+	}
+
+	for _, k := range opts.only {
+		if !found[k] {
+			return nil, fmt.Errorf("the key %s does not exist", k)
+		}
+	}
+
+	env := make([]string, 0, len(vars))
+
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+
+	sort.Strings(env)
+
+	return env, nil
+}
+
+// sanitizeEnvName converts the key to valid environment variable name, which consists of uppercase letters, digits
+// and underscores, and does not begin with a digit
+func sanitizeEnvName(key string) string {
+	var sb strings.Builder
+
+	for i, c := range key {
+		switch {
+		case 'a' <= c && c <= 'z':
+			sb.WriteRune(c - 'a' + 'A')
+		case 'A' <= c && c <= 'Z', c == '_':
+			sb.WriteRune(c)
+		case '0' <= c && c <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+
+			sb.WriteRune(c)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+
+	return sb.String()
+}
+
+// execCommand runs command with the given environment, forwarding SIGINT and SIGTERM to it.
+// exitError is returned if the command exits with non-zero status.
+func execCommand(command []string, env []string, in io.Reader, out io.Writer) error {
+	c := exec.Command(command[0], command[1:]...)
+	c.Env = env
+	c.Stdin = in
+	c.Stdout = out
+	c.Stderr = os.Stderr
+
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("run command %q: %w", command[0], err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				c.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := c.Wait(); err != nil {
+		var eerr *exec.ExitError
+		if errors.As(err, &eerr) {
+			code := eerr.ExitCode()
+			if code < 0 {
+				// killed by signal, reported in the same way as shells
+				code = 1
+
+				if ws, ok := eerr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+					code = 128 + int(ws.Signal())
+				}
+			}
+
+			return &exitError{code: code}
+		}
+
+		return fmt.Errorf("run command %q: %w", command[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSecretEnv(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rails",
+		},
+		Data: map[string][]byte{
+			"database-url": []byte("postgres://example.com:5432/dbname"),
+			"rails-env":    []byte("production"),
+			"1password":    []byte("p@ss=w0rd\n"),
+		},
+	}
+
+	testcases := map[string]struct {
+		secret  *v1.Secret
+		opts    runOpts
+		want    []string
+		wantErr error
+	}{
+		"as it is": {
+			secret: secret,
+			want: []string{
+				"1password=p@ss=w0rd\n",
+				"database-url=postgres://example.com:5432/dbname",
+				"rails-env=production",
+			},
+		},
+
+		"sanitize": {
+			secret: secret,
+			opts: runOpts{
+				sanitize: true,
+			},
+			want: []string{
+				"DATABASE_URL=postgres://example.com:5432/dbname",
+				"RAILS_ENV=production",
+				"_1PASSWORD=p@ss=w0rd\n",
+			},
+		},
+
+		"sanitize and prefix": {
+			secret: secret,
+			opts: runOpts{
+				prefix:   "APP_",
+				sanitize: true,
+			},
+			want: []string{
+				"APP_1PASSWORD=p@ss=w0rd\n",
+				"APP_DATABASE_URL=postgres://example.com:5432/dbname",
+				"APP_RAILS_ENV=production",
+			},
+		},
+
+		"only": {
+			secret: secret,
+			opts: runOpts{
+				only: []string{"rails-env"},
+			},
+			want: []string{
+				"rails-env=production",
+			},
+		},
+
+		"exclude": {
+			secret: secret,
+			opts: runOpts{
+				exclude: []string{"rails-env", "1password"},
+			},
+			want: []string{
+				"database-url=postgres://example.com:5432/dbname",
+			},
+		},
+
+		"only with missing key": {
+			secret: secret,
+			opts: runOpts{
+				only: []string{"rails-env", "foo"},
+			},
+			wantErr: errors.New("the key foo does not exist"),
+		},
+
+		"collision by sanitization": {
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"DATABASE_URL": []byte("foo"),
+					"database-url": []byte("bar"),
+				},
+			},
+			opts: runOpts{
+				sanitize: true,
+			},
+			wantErr: errors.New(`keys DATABASE_URL and database-url in secret "rails" are both converted to DATABASE_URL`),
+		},
+
+		"NUL character": {
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rails",
+				},
+				Data: map[string][]byte{
+					"binary": {'a', 0x00},
+				},
+			},
+			wantErr: errors.New(`value of key binary in secret "rails" contains NUL character, which cannot be used in environment variable`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: tc.secret,
+			}
+
+			got, err := secretEnv(context.Background(), k8sclient, namespace, []string{"rails"}, &tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Fatalf("want %q, got %q", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRunRun(t *testing.T) {
+	testcases := map[string]struct {
+		command []string
+		wantOut string
+		wantErr error
+	}{
+		"success": {
+			command: []string{"sh", "-c", `printf '%s|%s' "$DATABASE_URL" "$(cat)"`},
+			wantOut: "postgres://example.com:5432/dbname|input",
+		},
+
+		"exit status": {
+			command: []string{"sh", "-c", "exit 3"},
+			wantErr: &exitError{code: 3},
+		},
+
+		"command not found": {
+			command: []string{"k8sec-command-not-found"},
+			wantErr: errors.New(`run command "k8sec-command-not-found": exec: "k8sec-command-not-found": executable file not found in $PATH`),
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &fakeClient{
+				getSecretResponse: &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "rails",
+					},
+					Data: map[string][]byte{
+						"database-url": []byte("postgres://example.com:5432/dbname"),
+					},
+				},
+			}
+
+			var out bytes.Buffer
+
+			err := runRun(context.Background(), k8sclient, namespace, []string{"rails"}, tc.command, strings.NewReader("input"), &out, &runOpts{sanitize: true})
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if out.String() != tc.wantOut {
+					t.Fatalf("want %q, got %q", tc.wantOut, out.String())
+				}
+			}
+		})
+	}
+}
@@ -1,27 +1,43 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	v1 "k8s.io/api/core/v1"
 )
 
 type setOpts struct {
 	base64encoded bool
+	// secretType is the type of secret created if it does not exist
+	secretType string
+	fromFiles  []string
+	prompts    []string
+	// readPassword reads a value without echo after printing prompt
+	readPassword func(prompt string) ([]byte, error)
+	update       updateOpts
 }
 
-func newSetCmd(out io.Writer) *cobra.Command {
-	opts := setOpts{}
+func newSetCmd(in io.Reader, out io.Writer) *cobra.Command {
+	opts := setOpts{
+		readPassword: func(prompt string) ([]byte, error) {
+			return readPassword(in, os.Stderr, prompt)
+		},
+	}
 
 	setCmd := &cobra.Command{
-		Use:   "set NAME KEY1=VALUE1 [KEY2=VALUE2 ...]",
+		Use:   "set NAME [KEY1=VALUE1 KEY2=@FILE KEY3=- ...]",
 		Short: "Set secrets",
 		Long: `Set secrets
 
@@ -37,23 +53,49 @@ ZHRhbjQ=
 $ k8sec set --base64 rails foo=ZHRhbjQ=
 rails
 
+Values can be read from files, stdin and prompts so that they do not appear in shell history and process listings.
+File contents are stored byte-exact:
+
+$ k8sec set rails private-key=@private.pem
+$ k8sec set rails --from-file logo.png=./images/logo.png
+$ pbpaste | k8sec set rails api-token=-
+$ k8sec set rails --prompt database-password
+database-password:
+rails
+
+Secrets are created as Opaque if they do not exist. Use --type to create secrets of another type:
+
+$ k8sec set --type example.com/api-key rails api-key=@api-key.txt
+rails
+
+Secrets of the built-in types such as kubernetes.io/tls are validated after the values are set, and not updated if
+they become invalid, e.g. the certificate does not match the private key. Expiry of the certificate is checked only
+when tls.crt is set.
+
 Result:
 
 $ k8sec list rails
 NAME    TYPE    KEY             VALUE
 rails   Opaque  database-url    "postgres://example.com:5432/dbname"
 rails   Opaque  foo             "dtan4"
+
+When the secret is modified by others at the same time, the latest secret is got again and only the given keys are
+set to it. Use --if-resource-version to update the secret only if it has not been modified since it was got:
+
+$ k8sec list -o jsonpath='{.resourceVersion}' rails
+12345
+$ k8sec set --if-resource-version 12345 rails foo=bar
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
+			if len(args) < 1 || len(args) < 2 && len(opts.fromFiles) == 0 && len(opts.prompts) == 0 {
 				return fmt.Errorf("too few arguments")
 			}
 
 			ctx := context.Background()
 
-			k8sclient, err := client.New(rootOpts.kubeconfig, rootOpts.context)
+			k8sclient, err := newClient(out)
 			if err != nil {
-				return fmt.Errorf("initialize Kubernetes API client: %w", err)
+				return err
 			}
 
 			var namespace string
@@ -64,39 +106,25 @@ rails   Opaque  foo             "dtan4"
 				namespace = k8sclient.DefaultNamespace()
 			}
 
-			return runSet(ctx, k8sclient, namespace, args, out, &opts)
+			return runSet(ctx, k8sclient, namespace, args, in, out, &opts)
 		},
 	}
 
 	setCmd.Flags().BoolVar(&opts.base64encoded, "base64", false, "Decode the given value as base64-encoded string")
+	setCmd.Flags().StringVar(&opts.secretType, "type", string(v1.SecretTypeOpaque), "Type of the secret created if it does not exist")
+	setCmd.Flags().StringArrayVar(&opts.fromFiles, "from-file", []string{}, "Set the contents of file in KEY=PATH format")
+	setCmd.Flags().StringArrayVar(&opts.prompts, "prompt", []string{}, "Key whose value is read from interactive prompt without echo")
+	addUpdateFlags(setCmd.Flags(), &opts.update)
 
 	return setCmd
 }
 
-func runSet(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *setOpts) error {
+func runSet(ctx context.Context, k8sclient client.Client, namespace string, args []string, in io.Reader, out io.Writer, opts *setOpts) error {
 	name := args[0]
 
-	data := map[string][]byte{}
-
-	for _, kv := range args[1:] {
-		ary := strings.SplitN(kv, "=", 2)
-
-		if len(ary) != 2 {
-			return errors.New("argument should be in key=value format argument")
-		}
-
-		k, v := ary[0], ary[1]
-
-		if opts.base64encoded {
-			_v, err := base64.StdEncoding.DecodeString(v)
-			if err != nil {
-				return fmt.Errorf("decode value as base64-encoded string: %w", err)
-			}
-
-			data[k] = _v
-		} else {
-			data[k] = []byte(v)
-		}
+	data, err := readSetValues(args[1:], in, opts)
+	if err != nil {
+		return err
 	}
 
 	ss, err := k8sclient.ListSecrets(ctx, namespace)
@@ -121,31 +149,187 @@ func runSet(ctx context.Context, k8sclient client.Client, namespace string, args
 			return fmt.Errorf("get current secret %q: %w", name, err)
 		}
 
-		if s.Data == nil {
-			s.Data = data
-		} else {
+		_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+			if s.Data == nil {
+				s.Data = map[string][]byte{}
+			}
+
 			for k, v := range data {
 				s.Data[k] = v
 			}
-		}
 
-		_, err = k8sclient.UpdateSecret(ctx, namespace, s)
+			// Expiry is checked only for the certificate being set, so that the other keys of the secret with expired
+			// certificate can still be changed
+			var now time.Time
+			if _, ok := data[v1.TLSCertKey]; ok {
+				now = time.Now()
+			}
+
+			return secrettype.Validate(s, now)
+		})
 		if err != nil {
 			return fmt.Errorf("update secret %q: %w", name, err)
 		}
 	} else {
+		if opts.update.ifResourceVersion != "" {
+			return fmt.Errorf("secret %q does not exist", name)
+		}
+
+		secretType := v1.SecretType(opts.secretType)
+		if secretType == "" {
+			secretType = v1.SecretTypeOpaque
+		}
+
 		s = &v1.Secret{
+			Type: secretType,
 			Data: data,
 		}
 		s.SetName(name)
 
+		if err := secrettype.Validate(s, time.Now()); err != nil {
+			return fmt.Errorf("create secret %q: %w", name, err)
+		}
+
 		_, err = k8sclient.CreateSecret(ctx, namespace, s)
 		if err != nil {
 			return fmt.Errorf("create secret %q: %w", name, err)
 		}
 	}
 
-	fmt.Fprintln(out, s.Name)
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, s.Name)
+	}
 
 	return nil
 }
+
+// readSetValues reads values given in KEY=VALUE, KEY=@FILE and KEY=- (stdin) format, by --from-file and by --prompt
+func readSetValues(kvs []string, in io.Reader, opts *setOpts) (map[string][]byte, error) {
+	data := map[string][]byte{}
+
+	set := func(k string, v []byte) error {
+		if opts.base64encoded {
+			decoded, err := base64.StdEncoding.DecodeString(string(v))
+			if err != nil {
+				return fmt.Errorf("decode value of key %s as base64-encoded string: %w", k, err)
+			}
+
+			v = decoded
+		}
+
+		data[k] = v
+
+		return nil
+	}
+
+	stdinRead := false
+
+	for _, kv := range kvs {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, errors.New("argument should be in key=value format argument")
+		}
+
+		var value []byte
+
+		switch {
+		case v == "-":
+			if stdinRead {
+				return nil, errors.New("stdin can be read for only one key")
+			}
+
+			stdinRead = true
+
+			b, err := io.ReadAll(in)
+			if err != nil {
+				return nil, fmt.Errorf("read value of key %s from stdin: %w", k, err)
+			}
+
+			value = b
+		case strings.HasPrefix(v, "@"):
+			b, err := os.ReadFile(v[1:])
+			if err != nil {
+				return nil, fmt.Errorf("read value of key %s from file: %w", k, err)
+			}
+
+			value = b
+		default:
+			value = []byte(v)
+		}
+
+		if err := set(k, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, kv := range opts.fromFiles {
+		k, path, ok := strings.Cut(kv, "=")
+		if !ok || k == "" || path == "" {
+			return nil, fmt.Errorf("%q must be in KEY=PATH format", kv)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read value of key %s from file: %w", k, err)
+		}
+
+		if err := set(k, b); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, k := range opts.prompts {
+		b, err := opts.readPassword(k + ": ")
+		if err != nil {
+			return nil, fmt.Errorf("read value of key %s from prompt: %w", k, err)
+		}
+
+		if err := set(k, b); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// readPassword prints prompt to out and reads a line from in without echo. in must be a terminal.
+func readPassword(in io.Reader, out io.Writer, prompt string) ([]byte, error) {
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, errors.New("stdin is not a terminal")
+	}
+
+	fmt.Fprint(out, prompt)
+	defer fmt.Fprintln(out)
+
+	return term.ReadPassword(int(f.Fd()))
+}
+
+// readPasswordInput reads non-empty password from in if fromStdin is true, otherwise with readPassword
+func readPasswordInput(in io.Reader, fromStdin bool, readPassword func(prompt string) ([]byte, error)) ([]byte, error) {
+	var (
+		password []byte
+		err      error
+	)
+
+	if fromStdin {
+		password, err = io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("read password from stdin: %w", err)
+		}
+
+		// The trailing newline added by echo or here string is not a part of password, as docker login does
+		password = bytes.TrimRight(password, "\r\n")
+	} else {
+		password, err = readPassword("Password: ")
+		if err != nil {
+			return nil, fmt.Errorf("read password from prompt: %w", err)
+		}
+	}
+
+	if len(password) == 0 {
+		return nil, errors.New("password must not be empty")
+	}
+
+	return password, nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// lastAppliedConfigAnnotation is the annotation set by "kubectl apply", which refers the original object
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// stripSecret returns a copy of secret with the given name, which can be created as a new secret.
+// Server-populated metadata such as uid, resourceVersion and ownerReferences are dropped, and only the labels and
+// annotations whose keys are in keepLabels and keepAnnotations are kept. "*" keeps all of them.
+func stripSecret(secret *v1.Secret, name string, keepLabels, keepAnnotations []string) *v1.Secret {
+	s := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      selectKeys(secret.Labels, keepLabels),
+			Annotations: selectKeys(secret.Annotations, keepAnnotations),
+		},
+		Type: secret.Type,
+	}
+
+	delete(s.Annotations, lastAppliedConfigAnnotation)
+
+	if len(s.Annotations) == 0 {
+		s.Annotations = nil
+	}
+
+	if secret.Immutable != nil {
+		immutable := *secret.Immutable
+		s.Immutable = &immutable
+	}
+
+	if secret.Data != nil {
+		s.Data = make(map[string][]byte, len(secret.Data))
+
+		for k, v := range secret.Data {
+			s.Data[k] = append([]byte{}, v...)
+		}
+	}
+
+	return s
+}
+
+// selectKeys returns the entries of m whose keys are in keys. "*" selects all entries.
+func selectKeys(m map[string]string, keys []string) map[string]string {
+	selected := map[string]string{}
+
+	for _, key := range keys {
+		if key == "*" {
+			for k, v := range m {
+				selected[k] = v
+			}
+
+			break
+		}
+
+		if v, ok := m[key]; ok {
+			selected[key] = v
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil
+	}
+
+	return selected
+}
@@ -4,12 +4,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/dtan4/k8sec/pkg/secrettype"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
 )
 
+type unsetOpts struct {
+	update updateOpts
+}
+
 func newUnsetCmd(out io.Writer) *cobra.Command {
+	opts := unsetOpts{}
+
 	unsetCmd := &cobra.Command{
 		Use:   "unset KEY1 [KEY2 ...]",
 		Short: "Unset secrets",
@@ -20,9 +29,9 @@ func newUnsetCmd(out io.Writer) *cobra.Command {
 
 			ctx := context.Background()
 
-			k8sclient, err := client.New(rootOpts.kubeconfig, rootOpts.context)
+			k8sclient, err := newClient(out)
 			if err != nil {
-				return fmt.Errorf("initialize Kubernetes API client: %w", err)
+				return err
 			}
 
 			var namespace string
@@ -33,14 +42,16 @@ func newUnsetCmd(out io.Writer) *cobra.Command {
 				namespace = k8sclient.DefaultNamespace()
 			}
 
-			return runUnset(ctx, k8sclient, namespace, args, out)
+			return runUnset(ctx, k8sclient, namespace, args, out, &opts)
 		},
 	}
 
+	addUpdateFlags(unsetCmd.Flags(), &opts.update)
+
 	return unsetCmd
 }
 
-func runUnset(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer) error {
+func runUnset(ctx context.Context, k8sclient client.Client, namespace string, args []string, out io.Writer, opts *unsetOpts) error {
 	name := args[0]
 
 	s, err := k8sclient.GetSecret(ctx, namespace, name)
@@ -48,21 +59,26 @@ func runUnset(ctx context.Context, k8sclient client.Client, namespace string, ar
 		return fmt.Errorf("get current secret %q: %w", name, err)
 	}
 
-	for _, k := range args[1:] {
-		_, ok := s.Data[k]
-		if !ok {
-			return fmt.Errorf("the key %s does not exist", k)
-		}
+	_, err = updateSecret(ctx, k8sclient, namespace, s, &opts.update, func(s *v1.Secret) error {
+		for _, k := range args[1:] {
+			_, ok := s.Data[k]
+			if !ok {
+				return fmt.Errorf("the key %s does not exist", k)
+			}
 
-		delete(s.Data, k)
-	}
+			delete(s.Data, k)
+		}
 
-	_, err = k8sclient.UpdateSecret(ctx, namespace, s)
+		// Expiry of certificates is not checked, which is not changed by unsetting keys
+		return secrettype.Validate(s, time.Time{})
+	})
 	if err != nil {
 		return fmt.Errorf("unset secret %q: %w", name, err)
 	}
 
-	fmt.Fprintln(out, s.Name)
+	if !isDryRun(k8sclient) {
+		fmt.Fprintln(out, s.Name)
+	}
 
 	return nil
 }
@@ -37,6 +37,24 @@ func TestUnSet(t *testing.T) {
 
 		// TODO: add testcase for no matched error found
 
+		"unset required key of typed secret": {
+			args: []string{
+				"nginx",
+				"tls.key",
+			},
+			secret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nginx",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					"tls.crt": []byte("thisiscrt"),
+					"tls.key": []byte("thisiskey"),
+				},
+			},
+			wantErr: errors.New(`unset secret "nginx": secret of type kubernetes.io/tls must have key tls.key`),
+		},
+
 		"error at get secret": {
 			args: []string{
 				"rails",
@@ -61,7 +79,7 @@ func TestUnSet(t *testing.T) {
 
 			var out bytes.Buffer
 
-			err := runUnset(context.Background(), k8sclient, namespace, tc.args, &out)
+			err := runUnset(context.Background(), k8sclient, namespace, tc.args, &out, &unsetOpts{})
 
 			if tc.wantErr != nil {
 				if err == nil {
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dtan4/k8sec/pkg/client"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+const defaultConflictRetries = 5
+
+type updateOpts struct {
+	conflictRetries   int
+	ifResourceVersion string
+}
+
+func addUpdateFlags(flags *pflag.FlagSet, opts *updateOpts) {
+	flags.IntVar(&opts.conflictRetries, "conflict-retries", defaultConflictRetries, "Number of retries when the secret is modified by others at the same time")
+	flags.StringVar(&opts.ifResourceVersion, "if-resource-version", "", "Update the secret only if its resourceVersion is the given one, without retries")
+}
+
+// updateSecret applies mutate to the secret s got from API server, and updates it.
+//
+// If the secret has been modified by others since s was got, the latest secret is got again and mutate is applied to
+// it, up to opts.conflictRetries times. mutate should apply only the changes requested by user so that the changes by
+// others are kept.
+//
+// If opts.ifResourceVersion is given, the secret is updated only if its resourceVersion matches without any retry.
+func updateSecret(ctx context.Context, k8sclient client.Client, namespace string, s *v1.Secret, opts *updateOpts, mutate func(s *v1.Secret) error) (*v1.Secret, error) {
+	if opts.conflictRetries < 0 {
+		return nil, fmt.Errorf("--conflict-retries must not be negative, got %d", opts.conflictRetries)
+	}
+
+	backoff := retry.DefaultRetry
+	backoff.Steps = opts.conflictRetries + 1
+
+	if opts.ifResourceVersion != "" {
+		if s.ResourceVersion != opts.ifResourceVersion {
+			return nil, newResourceVersionConflict(s, opts.ifResourceVersion)
+		}
+
+		backoff.Steps = 1
+	}
+
+	var (
+		updated *v1.Secret
+		first   = true
+	)
+
+	err := retry.RetryOnConflict(backoff, func() error {
+		if !first {
+			latest, err := k8sclient.GetSecret(ctx, namespace, s.Name)
+			if err != nil {
+				return fmt.Errorf("get latest secret %q: %w", s.Name, err)
+			}
+
+			s = latest
+		}
+
+		first = false
+
+		if err := mutate(s); err != nil {
+			return err
+		}
+
+		var err error
+
+		updated, err = k8sclient.UpdateSecret(ctx, namespace, s)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func newResourceVersionConflict(s *v1.Secret, resourceVersion string) error {
+	return apierrors.NewConflict(
+		schema.GroupResource{Resource: "secrets"},
+		s.Name,
+		fmt.Errorf("resourceVersion is %s, not %s", s.ResourceVersion, resourceVersion),
+	)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// conflictingClient returns conflict error at UpdateSecret for the first conflicts times,
+// as if the secret were modified by others
+type conflictingClient struct {
+	fakeClient
+
+	conflicts int
+	updates   int
+}
+
+func (c *conflictingClient) UpdateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
+	c.updates++
+
+	if c.updates <= c.conflicts {
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, secret.Name, nil)
+	}
+
+	return c.fakeClient.UpdateSecret(ctx, namespace, secret)
+}
+
+func TestUpdateSecret(t *testing.T) {
+	testcases := map[string]struct {
+		opts        updateOpts
+		conflicts   int
+		wantData    map[string][]byte
+		wantUpdates int
+		wantErr     bool
+	}{
+		"no conflict": {
+			opts: updateOpts{
+				conflictRetries: 5,
+			},
+			conflicts: 0,
+			wantData: map[string][]byte{
+				"foo": []byte("bar"),
+			},
+			wantUpdates: 1,
+		},
+
+		"retry on conflict with the latest secret": {
+			opts: updateOpts{
+				conflictRetries: 5,
+			},
+			conflicts: 2,
+			wantData: map[string][]byte{
+				"foo":   []byte("bar"),
+				"other": []byte("added by others"),
+			},
+			wantUpdates: 3,
+		},
+
+		"too many conflicts": {
+			opts: updateOpts{
+				conflictRetries: 1,
+			},
+			conflicts:   2,
+			wantUpdates: 2,
+			wantErr:     true,
+		},
+
+		"resourceVersion matched": {
+			opts: updateOpts{
+				conflictRetries:   5,
+				ifResourceVersion: "1",
+			},
+			conflicts: 0,
+			wantData: map[string][]byte{
+				"foo": []byte("bar"),
+			},
+			wantUpdates: 1,
+		},
+
+		"resourceVersion matched but conflicted": {
+			opts: updateOpts{
+				conflictRetries:   5,
+				ifResourceVersion: "1",
+			},
+			conflicts:   1,
+			wantUpdates: 1,
+			wantErr:     true,
+		},
+
+		"resourceVersion mismatched": {
+			opts: updateOpts{
+				conflictRetries:   5,
+				ifResourceVersion: "100",
+			},
+			wantUpdates: 0,
+			wantErr:     true,
+		},
+	}
+
+	namespace := "test"
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			current := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "rails",
+					ResourceVersion: "1",
+				},
+			}
+
+			latest := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "rails",
+					ResourceVersion: "2",
+				},
+				Data: map[string][]byte{
+					"other": []byte("added by others"),
+				},
+			}
+
+			k8sclient := &conflictingClient{
+				fakeClient: fakeClient{
+					getSecretResponse: latest,
+				},
+				conflicts: tc.conflicts,
+			}
+
+			_, err := updateSecret(context.Background(), k8sclient, namespace, current, &tc.opts, func(s *v1.Secret) error {
+				if s.Data == nil {
+					s.Data = map[string][]byte{}
+				}
+
+				s.Data["foo"] = []byte("bar")
+
+				return nil
+			})
+
+			if k8sclient.updates != tc.wantUpdates {
+				t.Fatalf("want %d updates, got %d", tc.wantUpdates, k8sclient.updates)
+			}
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("want error, got no error")
+				}
+
+				if !apierrors.IsConflict(err) {
+					t.Fatalf("want conflict error, got %q", err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if !reflect.DeepEqual(k8sclient.updatedSecret.Data, tc.wantData) {
+				t.Fatalf("want data %#v, got %#v", tc.wantData, k8sclient.updatedSecret.Data)
+			}
+		})
+	}
+}
@@ -14,20 +14,38 @@ import (
 
 // Client represents Kubernetes client and calculated namespace
 type Client interface {
+	ContextName() string
 	DefaultNamespace() string
 	CreateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error)
+	DeleteSecret(ctx context.Context, namespace, name string, opts DeleteOptions) error
 	GetSecret(ctx context.Context, namespace, name string) (*v1.Secret, error)
 	ListSecrets(ctx context.Context, namespace string) (*v1.SecretList, error)
 	UpdateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error)
 }
 
+// DeleteOptions represents the options of deleting Secret
+type DeleteOptions struct {
+	// PropagationPolicy determines how the dependents are garbage collected. API server default is used if nil.
+	PropagationPolicy *metav1.DeletionPropagation
+	// GracePeriodSeconds is the duration before the secret is deleted. API server default is used if nil.
+	GracePeriodSeconds *int64
+	// Preconditions makes the deletion fail with conflict error if UID or ResourceVersion of the secret differs.
+	// No precondition is checked if nil.
+	Preconditions *metav1.Preconditions
+}
+
 type clientImpl struct {
 	clientset kubernetes.Interface
 	rawConfig api.Config
+	// context overrides the current context in kubeconfig if not empty
+	context string
+	// dryRun makes all modifying requests server-side dry run
+	dryRun bool
 }
 
-// New creates new Kubernetes API client
-func New(kubeconfig, context string) (*clientImpl, error) {
+// New creates new Kubernetes API client. If dryRun is true, modifying requests are processed by API server without
+// being persisted.
+func New(kubeconfig, context string, dryRun bool) (*clientImpl, error) {
 	if kubeconfig == "" {
 		kubeconfig = clientcmd.RecommendedHomeFile
 	}
@@ -57,25 +75,45 @@ func New(kubeconfig, context string) (*clientImpl, error) {
 	return &clientImpl{
 		clientset: clientset,
 		rawConfig: rawConfig,
+		context:   context,
+		dryRun:    dryRun,
 	}, nil
 }
 
-// DefaultNamespace returns the default namespace in kubeconfig
-func (c *clientImpl) DefaultNamespace() string {
-	var namespace string
+// ContextName returns the name of the context used by the client, which is the current context in kubeconfig if not
+// overridden
+func (c *clientImpl) ContextName() string {
+	if c.context != "" {
+		return c.context
+	}
 
-	if c.rawConfig.Contexts[c.rawConfig.CurrentContext].Namespace == "" {
-		namespace = v1.NamespaceDefault
-	} else {
-		namespace = c.rawConfig.Contexts[c.rawConfig.CurrentContext].Namespace
+	return c.rawConfig.CurrentContext
+}
+
+// DefaultNamespace returns the default namespace of the context in kubeconfig
+func (c *clientImpl) DefaultNamespace() string {
+	if ctx, ok := c.rawConfig.Contexts[c.ContextName()]; ok && ctx.Namespace != "" {
+		return ctx.Namespace
 	}
 
-	return namespace
+	return v1.NamespaceDefault
 }
 
 // CreateSecret creates new Secret
 func (c *clientImpl) CreateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
-	return c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{
+		DryRun: c.dryRunOption(),
+	})
+}
+
+// DeleteSecret deletes the secret with the given name
+func (c *clientImpl) DeleteSecret(ctx context.Context, namespace, name string, opts DeleteOptions) error {
+	return c.clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		DryRun:             c.dryRunOption(),
+		PropagationPolicy:  opts.PropagationPolicy,
+		GracePeriodSeconds: opts.GracePeriodSeconds,
+		Preconditions:      opts.Preconditions,
+	})
 }
 
 // GetSecret returns secret with the given name
@@ -90,5 +128,15 @@ func (c *clientImpl) ListSecrets(ctx context.Context, namespace string) (*v1.Sec
 
 // UpdateSecret updates the existed secret
 func (c *clientImpl) UpdateSecret(ctx context.Context, namespace string, secret *v1.Secret) (*v1.Secret, error) {
-	return c.clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return c.clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{
+		DryRun: c.dryRunOption(),
+	})
+}
+
+func (c *clientImpl) dryRunOption() []string {
+	if c.dryRun {
+		return []string{metav1.DryRunAll}
+	}
+
+	return nil
 }
@@ -6,11 +6,91 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+func TestContextName(t *testing.T) {
+	rawConfig := api.Config{
+		CurrentContext: "staging",
+	}
+
+	testcases := map[string]struct {
+		context string
+		want    string
+	}{
+		"current context": {
+			context: "",
+			want:    "staging",
+		},
+		"overridden context": {
+			context: "production",
+			want:    "production",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			client := &clientImpl{
+				rawConfig: rawConfig,
+				context:   tc.context,
+			}
+
+			if got := client.ContextName(); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultNamespace(t *testing.T) {
+	rawConfig := api.Config{
+		CurrentContext: "staging",
+		Contexts: map[string]*api.Context{
+			"staging": {
+				Namespace: "app",
+			},
+			"production": {},
+		},
+	}
+
+	testcases := map[string]struct {
+		context string
+		want    string
+	}{
+		"current context": {
+			context: "",
+			want:    "app",
+		},
+		"overridden context": {
+			context: "production",
+			want:    "default",
+		},
+		"unknown context": {
+			context: "unknown",
+			want:    "default",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			client := &clientImpl{
+				rawConfig: rawConfig,
+				context:   tc.context,
+			}
+
+			if got := client.DefaultNamespace(); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestCreateSecret(t *testing.T) {
 	testcases := map[string]struct {
 		namespace string
@@ -58,6 +138,97 @@ func TestCreateSecret(t *testing.T) {
 	}
 }
 
+func TestDeleteSecret(t *testing.T) {
+	background := metav1.DeletePropagationBackground
+	gracePeriod := int64(0)
+	uid := types.UID("1234")
+	resourceVersion := "5678"
+
+	testcases := map[string]struct {
+		namespace string
+		name      string
+		opts      DeleteOptions
+		wantErr   bool
+	}{
+		"success": {
+			namespace: "test",
+			name:      "example",
+		},
+		"with options": {
+			namespace: "test",
+			name:      "example",
+			opts: DeleteOptions{
+				PropagationPolicy:  &background,
+				GracePeriodSeconds: &gracePeriod,
+			},
+		},
+		"with preconditions": {
+			namespace: "test",
+			name:      "example",
+			opts: DeleteOptions{
+				Preconditions: &metav1.Preconditions{
+					UID:             &uid,
+					ResourceVersion: &resourceVersion,
+				},
+			},
+		},
+		"not found": {
+			namespace: "test",
+			name:      "unknown",
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example",
+					Namespace: "test",
+				},
+			}
+
+			clientset := fake.NewSimpleClientset(secret)
+			client := &clientImpl{
+				clientset: clientset,
+			}
+
+			ctx := context.Background()
+
+			err := client.DeleteSecret(ctx, tc.namespace, tc.name, tc.opts)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("want error, got no error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if _, err := clientset.CoreV1().Secrets(tc.namespace).Get(ctx, tc.name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+				t.Errorf("secret %s must be deleted, error: %v", tc.name, err)
+			}
+
+			action := clientset.Actions()[0].(k8stesting.DeleteActionImpl)
+
+			if got := action.DeleteOptions.PropagationPolicy; !reflect.DeepEqual(got, tc.opts.PropagationPolicy) {
+				t.Errorf("propagation policy want %v, got %v", tc.opts.PropagationPolicy, got)
+			}
+
+			if got := action.DeleteOptions.GracePeriodSeconds; !reflect.DeepEqual(got, tc.opts.GracePeriodSeconds) {
+				t.Errorf("grace period want %v, got %v", tc.opts.GracePeriodSeconds, got)
+			}
+
+			if got := action.DeleteOptions.Preconditions; !reflect.DeepEqual(got, tc.opts.Preconditions) {
+				t.Errorf("preconditions want %v, got %v", tc.opts.Preconditions, got)
+			}
+		})
+	}
+}
+
 func TestGetSecret(t *testing.T) {
 	testcases := map[string]struct {
 		namespace string
@@ -206,3 +377,67 @@ func TestUpdateSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"foo": []byte("bar"),
+		},
+	}
+
+	testcases := map[string]struct {
+		dryRun bool
+		want   []string
+	}{
+		"dry run": {
+			dryRun: true,
+			want:   []string{metav1.DryRunAll},
+		},
+		"no dry run": {
+			dryRun: false,
+			want:   nil,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			client := &clientImpl{
+				clientset: clientset,
+				dryRun:    tc.dryRun,
+			}
+
+			ctx := context.Background()
+
+			if _, err := client.CreateSecret(ctx, "test", secret); err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if _, err := client.UpdateSecret(ctx, "test", secret); err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if err := client.DeleteSecret(ctx, "test", secret.Name, DeleteOptions{}); err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			actions := clientset.Actions()
+
+			if got := actions[0].(k8stesting.CreateActionImpl).CreateOptions.DryRun; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("create options want DryRun %#v, got %#v", tc.want, got)
+			}
+
+			if got := actions[1].(k8stesting.UpdateActionImpl).UpdateOptions.DryRun; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("update options want DryRun %#v, got %#v", tc.want, got)
+			}
+
+			if got := actions[2].(k8stesting.DeleteActionImpl).DeleteOptions.DryRun; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("delete options want DryRun %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
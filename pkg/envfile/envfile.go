@@ -0,0 +1,109 @@
+package envfile
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ShellLine returns "export KEY='value'" line, which can be evaluated with source or eval in POSIX shells.
+// Values are single-quoted so that $, backticks and backslashes are never interpreted.
+func ShellLine(key string, value []byte) (string, error) {
+	if !isIdentifier(key) {
+		return "", fmt.Errorf("the key %s cannot be used as shell variable name", key)
+	}
+
+	if strings.IndexByte(string(value), 0) >= 0 {
+		return "", fmt.Errorf("value of key %s contains NUL character, which cannot be used in shell variable", key)
+	}
+
+	return "export " + key + "=" + shellQuote(string(value)), nil
+}
+
+// DockerLine returns "KEY=value" line of the file given to "docker run --env-file".
+// Docker takes values as they are without unquoting, so values cannot contain newlines.
+func DockerLine(key string, value []byte) (string, error) {
+	if key == "" || strings.HasPrefix(key, "#") || strings.ContainsAny(key, "= \t\r\n") {
+		return "", fmt.Errorf("the key %s cannot be used as docker env-file variable name", key)
+	}
+
+	if err := checkValue(key, value, "docker env-file"); err != nil {
+		return "", err
+	}
+
+	if strings.ContainsAny(string(value), "\r\n") {
+		return "", fmt.Errorf("value of key %s contains newline, which cannot be used in docker env-file", key)
+	}
+
+	return key + "=" + string(value), nil
+}
+
+// SystemdLine returns KEY="value" line of the file given to EnvironmentFile= of systemd units.
+// Values are double-quoted with \, ", ` and $ escaped, so that leading and trailing whitespaces and newlines are kept.
+func SystemdLine(key string, value []byte) (string, error) {
+	if !isIdentifier(key) {
+		return "", fmt.Errorf("the key %s cannot be used as systemd environment variable name", key)
+	}
+
+	if err := checkValue(key, value, "systemd EnvironmentFile"); err != nil {
+		return "", err
+	}
+
+	for _, r := range string(value) {
+		if r < 0x20 && r != '\t' && r != '\n' || r == 0x7f {
+			return "", fmt.Errorf("value of key %s contains control character %U, which cannot be used in systemd EnvironmentFile", key, r)
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(key + `="`)
+
+	for _, r := range string(value) {
+		if strings.ContainsRune("\\\"`$", r) {
+			sb.WriteByte('\\')
+		}
+
+		sb.WriteRune(r)
+	}
+
+	sb.WriteByte('"')
+
+	return sb.String(), nil
+}
+
+// checkValue returns error if value is not valid UTF-8 text without NUL character
+func checkValue(key string, value []byte, format string) error {
+	if !utf8.Valid(value) {
+		return fmt.Errorf("value of key %s is not valid UTF-8, which cannot be used in %s", key, format)
+	}
+
+	if strings.IndexByte(string(value), 0) >= 0 {
+		return fmt.Errorf("value of key %s contains NUL character, which cannot be used in %s", key, format)
+	}
+
+	return nil
+}
+
+// shellQuote returns s in single quotes. Each single quote in s closes the quotes, is escaped and reopens them.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isIdentifier reports whether s consists of letters, digits and underscores and does not begin with a digit
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,316 @@
+package generate
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Formats of generated values
+const (
+	FormatPassword   = "password"
+	FormatHex        = "hex"
+	FormatBase64     = "base64"
+	FormatBase64URL  = "base64url"
+	FormatUUID       = "uuid"
+	FormatPassphrase = "passphrase"
+)
+
+// Character classes used in passwords
+const (
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+var classChars = map[string]string{
+	ClassLower:  "abcdefghijklmnopqrstuvwxyz",
+	ClassUpper:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	ClassDigit:  "0123456789",
+	ClassSymbol: "!#%&()*+-./:;<=>?@[]^_{|}~",
+}
+
+// classAliases are the names of frequently used sets of character classes
+var classAliases = map[string][]string{
+	"alnum": {ClassLower, ClassUpper, ClassDigit},
+	"all":   {ClassLower, ClassUpper, ClassDigit, ClassSymbol},
+}
+
+// defaultLengths are the default lengths of each format.
+// Length means characters for password, bytes for hex and base64, and words for passphrase.
+var defaultLengths = map[string]int{
+	FormatPassword:   32,
+	FormatHex:        32,
+	FormatBase64:     32,
+	FormatBase64URL:  32,
+	FormatPassphrase: 6,
+}
+
+//go:embed words.txt
+var wordList string
+
+var words = strings.Fields(wordList)
+
+// Spec represents how to generate a value
+type Spec struct {
+	Format string
+	// Length is the number of characters for password, bytes for hex and base64, and words for passphrase.
+	// The default length of the format is used if 0.
+	Length int
+	// Classes are the character classes used in password. Lowercase and uppercase letters and digits are used if empty.
+	Classes []string
+	// Alphabet is the characters used in password instead of Classes if not empty
+	Alphabet string
+	// Separator is placed between words in passphrase
+	Separator string
+}
+
+// ParseSpec parses comma-separated options like "password,length=32,charset=lower+digit".
+//
+// The following options are supported:
+//
+//	password, hex, base64, base64url, uuid, passphrase  format (default: password)
+//	N or length=N                                       length
+//	charset=CLASS[+CLASS...]                            character classes of password: lower, upper, digit, symbol, alnum or all
+//	alphabet=CHARS                                      characters of password, which cannot contain ","
+//	separator=SEP                                       separator of passphrase words (default: "-")
+//
+// Empty string means the default spec.
+func ParseSpec(s string) (Spec, error) {
+	spec := Spec{
+		Format:    FormatPassword,
+		Separator: "-",
+	}
+
+	if s == "" {
+		return spec, nil
+	}
+
+	for _, opt := range strings.Split(s, ",") {
+		name, value, hasValue := strings.Cut(opt, "=")
+
+		if !hasValue {
+			if n, err := strconv.Atoi(name); err == nil {
+				name, value = "length", strconv.Itoa(n)
+			} else {
+				name, value = "format", name
+			}
+		}
+
+		switch name {
+		case "format":
+			if _, ok := defaultLengths[value]; !ok && value != FormatUUID {
+				return Spec{}, fmt.Errorf("unknown format %q", value)
+			}
+
+			spec.Format = value
+		case "length":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return Spec{}, fmt.Errorf("length must be a positive integer, got %q", value)
+			}
+
+			spec.Length = n
+		case "charset":
+			classes := []string{}
+
+			for _, class := range strings.Split(value, "+") {
+				members, ok := classAliases[class]
+				if !ok {
+					if _, ok := classChars[class]; !ok {
+						return Spec{}, fmt.Errorf("unknown charset %q", class)
+					}
+
+					members = []string{class}
+				}
+
+				// Overlapping classes such as alnum+lower are added once, not to skew the distribution
+				for _, member := range members {
+					if !containsString(classes, member) {
+						classes = append(classes, member)
+					}
+				}
+			}
+
+			spec.Classes = classes
+		case "alphabet":
+			if len(uniqueRunes(value)) < 2 {
+				return Spec{}, fmt.Errorf("alphabet must have at least 2 distinct characters, got %q", value)
+			}
+
+			spec.Alphabet = value
+		case "separator":
+			spec.Separator = value
+		default:
+			return Spec{}, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+
+	if spec.Format == FormatUUID && spec.Length != 0 {
+		return Spec{}, fmt.Errorf("length cannot be specified for %s", FormatUUID)
+	}
+
+	if spec.Format != FormatPassword && (len(spec.Classes) > 0 || spec.Alphabet != "") {
+		return Spec{}, fmt.Errorf("charset and alphabet can be specified only for %s", FormatPassword)
+	}
+
+	return spec, nil
+}
+
+// Generate generates a random value with crypto/rand
+func Generate(spec Spec) (string, error) {
+	length := spec.Length
+	if length == 0 {
+		length = defaultLengths[spec.Format]
+	}
+
+	switch spec.Format {
+	case FormatPassword, "":
+		return generatePassword(length, spec.Classes, spec.Alphabet)
+	case FormatHex:
+		b, err := randomBytes(length)
+		if err != nil {
+			return "", err
+		}
+
+		return hex.EncodeToString(b), nil
+	case FormatBase64:
+		b, err := randomBytes(length)
+		if err != nil {
+			return "", err
+		}
+
+		return base64.StdEncoding.EncodeToString(b), nil
+	case FormatBase64URL:
+		b, err := randomBytes(length)
+		if err != nil {
+			return "", err
+		}
+
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case FormatUUID:
+		b, err := randomBytes(16)
+		if err != nil {
+			return "", err
+		}
+
+		// version 4 and RFC 4122 variant
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+	case FormatPassphrase:
+		ws := make([]string, 0, length)
+
+		for i := 0; i < length; i++ {
+			n, err := randomInt(len(words))
+			if err != nil {
+				return "", err
+			}
+
+			ws = append(ws, words[n])
+		}
+
+		return strings.Join(ws, spec.Separator), nil
+	default:
+		return "", fmt.Errorf("unknown format %q", spec.Format)
+	}
+}
+
+// generatePassword generates a password which contains at least one character of each class if length allows
+func generatePassword(length int, classes []string, alphabet string) (string, error) {
+	var sets []string
+
+	if alphabet != "" {
+		sets = []string{alphabet}
+	} else {
+		if len(classes) == 0 {
+			classes = classAliases["alnum"]
+		}
+
+		for _, class := range classes {
+			sets = append(sets, classChars[class])
+		}
+	}
+
+	// Each character is sampled with the same probability even if it appears in the sets more than once
+	chars := uniqueRunes(strings.Join(sets, ""))
+
+	for {
+		password := make([]rune, 0, length)
+
+		for i := 0; i < length; i++ {
+			n, err := randomInt(len(chars))
+			if err != nil {
+				return "", err
+			}
+
+			password = append(password, chars[n])
+		}
+
+		if length < len(sets) || containsAllSets(string(password), sets) {
+			return string(password), nil
+		}
+	}
+}
+
+func containsAllSets(s string, sets []string) bool {
+	for _, set := range sets {
+		if !strings.ContainsAny(s, set) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// uniqueRunes returns the characters of s in order of first appearance, without duplicates
+func uniqueRunes(s string) []rune {
+	seen := map[rune]bool{}
+	rs := []rune{}
+
+	for _, r := range s {
+		if !seen[r] {
+			seen[r] = true
+			rs = append(rs, r)
+		}
+	}
+
+	return rs
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return b, nil
+}
+
+// randomInt returns uniform random integer in [0, max)
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, fmt.Errorf("generate random number: %w", err)
+	}
+
+	return int(n.Int64()), nil
+}
@@ -0,0 +1,278 @@
+package generate
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	testcases := map[string]struct {
+		s       string
+		want    Spec
+		wantErr error
+	}{
+		"default": {
+			s: "",
+			want: Spec{
+				Format:    FormatPassword,
+				Separator: "-",
+			},
+		},
+		"length only": {
+			s: "64",
+			want: Spec{
+				Format:    FormatPassword,
+				Length:    64,
+				Separator: "-",
+			},
+		},
+		"password with charset": {
+			s: "password,length=16,charset=alnum+symbol",
+			want: Spec{
+				Format:    FormatPassword,
+				Length:    16,
+				Classes:   []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol},
+				Separator: "-",
+			},
+		},
+		"overlapping charsets": {
+			s: "charset=alnum+lower+digit+symbol",
+			want: Spec{
+				Format:    FormatPassword,
+				Classes:   []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol},
+				Separator: "-",
+			},
+		},
+		"password with alphabet": {
+			s: "alphabet=abc123",
+			want: Spec{
+				Format:    FormatPassword,
+				Alphabet:  "abc123",
+				Separator: "-",
+			},
+		},
+		"hex": {
+			s: "hex,16",
+			want: Spec{
+				Format:    FormatHex,
+				Length:    16,
+				Separator: "-",
+			},
+		},
+		"passphrase": {
+			s: "passphrase,length=4,separator=.",
+			want: Spec{
+				Format:    FormatPassphrase,
+				Length:    4,
+				Separator: ".",
+			},
+		},
+		"unknown format": {
+			s:       "rot13",
+			wantErr: errors.New(`unknown format "rot13"`),
+		},
+		"invalid length": {
+			s:       "length=0",
+			wantErr: errors.New(`length must be a positive integer, got "0"`),
+		},
+		"unknown charset": {
+			s:       "charset=lower+emoji",
+			wantErr: errors.New(`unknown charset "emoji"`),
+		},
+		"alphabet with duplicated character only": {
+			s:       "alphabet=aaa",
+			wantErr: errors.New(`alphabet must have at least 2 distinct characters, got "aaa"`),
+		},
+		"unknown option": {
+			s:       "foo=bar",
+			wantErr: errors.New(`unknown option "foo=bar"`),
+		},
+		"length of uuid": {
+			s:       "uuid,16",
+			wantErr: errors.New("length cannot be specified for uuid"),
+		},
+		"charset of hex": {
+			s:       "hex,charset=digit",
+			wantErr: errors.New("charset and alphabet can be specified only for password"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSpec(tc.s)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Fatalf("want %#v, got %#v", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	testcases := map[string]struct {
+		spec  string
+		check func(t *testing.T, v string)
+	}{
+		"default password": {
+			spec: "",
+			check: func(t *testing.T, v string) {
+				if !regexp.MustCompile(`^[a-zA-Z0-9]{32}$`).MatchString(v) {
+					t.Errorf("want 32 alphanumeric characters, got %q", v)
+				}
+
+				for _, class := range []string{ClassLower, ClassUpper, ClassDigit} {
+					if !strings.ContainsAny(v, classChars[class]) {
+						t.Errorf("want %s characters in %q", class, v)
+					}
+				}
+			},
+		},
+		"password with symbols": {
+			spec: "8,charset=digit+symbol",
+			check: func(t *testing.T, v string) {
+				if len(v) != 8 {
+					t.Errorf("want 8 characters, got %q", v)
+				}
+
+				if strings.Trim(v, classChars[ClassDigit]+classChars[ClassSymbol]) != "" {
+					t.Errorf("want digits and symbols only, got %q", v)
+				}
+
+				if !strings.ContainsAny(v, classChars[ClassSymbol]) || !strings.ContainsAny(v, classChars[ClassDigit]) {
+					t.Errorf("want both digits and symbols, got %q", v)
+				}
+			},
+		},
+		"password with alphabet": {
+			spec: "alphabet=あい,10",
+			check: func(t *testing.T, v string) {
+				if !regexp.MustCompile(`^[あい]{10}$`).MatchString(v) {
+					t.Errorf("want 10 characters of the alphabet, got %q", v)
+				}
+			},
+		},
+		"hex": {
+			spec: "hex,16",
+			check: func(t *testing.T, v string) {
+				b, err := hex.DecodeString(v)
+				if err != nil || len(b) != 16 {
+					t.Errorf("want 16 bytes in hex, got %q", v)
+				}
+			},
+		},
+		"base64": {
+			spec: "base64",
+			check: func(t *testing.T, v string) {
+				b, err := base64.StdEncoding.DecodeString(v)
+				if err != nil || len(b) != 32 {
+					t.Errorf("want 32 bytes in base64, got %q", v)
+				}
+			},
+		},
+		"base64url": {
+			spec: "base64url,64",
+			check: func(t *testing.T, v string) {
+				b, err := base64.RawURLEncoding.DecodeString(v)
+				if err != nil || len(b) != 64 {
+					t.Errorf("want 64 bytes in base64url, got %q", v)
+				}
+			},
+		},
+		"uuid": {
+			spec: "uuid",
+			check: func(t *testing.T, v string) {
+				if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(v) {
+					t.Errorf("want UUID version 4, got %q", v)
+				}
+			},
+		},
+		"passphrase": {
+			spec: "passphrase,4,separator=_",
+			check: func(t *testing.T, v string) {
+				ws := strings.Split(v, "_")
+				if len(ws) != 4 {
+					t.Fatalf("want 4 words, got %q", v)
+				}
+
+				for _, w := range ws {
+					if !regexp.MustCompile(`^[a-z]+$`).MatchString(w) {
+						t.Errorf("want lowercase word, got %q", w)
+					}
+				}
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := ParseSpec(tc.spec)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			v, err := Generate(spec)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			tc.check(t, v)
+
+			other, err := Generate(spec)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err.Error())
+			}
+
+			if v == other {
+				t.Errorf("want different values, got %q twice", v)
+			}
+		})
+	}
+}
+
+func TestUniqueRunes(t *testing.T) {
+	got := string(uniqueRunes("abcaあbあ"))
+	if got != "abcあ" {
+		t.Errorf("want %q, got %q", "abcあ", got)
+	}
+}
+
+func TestWords(t *testing.T) {
+	if len(words) < 1024 {
+		t.Errorf("want at least 1024 words, got %d", len(words))
+	}
+
+	seen := map[string]bool{}
+
+	for _, w := range words {
+		if seen[w] {
+			t.Errorf("word %q is duplicated", w)
+		}
+
+		seen[w] = true
+	}
+}
@@ -0,0 +1,281 @@
+package keyvalue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	yaml3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// base64Field is the field of the object representing binary value, e.g. {"base64": "iVBORw0KGgo="}
+const base64Field = "base64"
+
+// Options are the options to parse JSON and YAML
+type Options struct {
+	// Flatten loads nested objects with the keys joined with Separator, e.g. {"database": {"url": "..."}} is loaded
+	// as database_url if Separator is "_". Nested objects are error if false.
+	Flatten   bool
+	Separator string
+}
+
+// ParseJSON parses JSON object of key to value. filename is used in error messages only.
+//
+// Values are strings, numbers and booleans, which are stored as their literal text, or {"base64": "..."} for binary
+// values.
+func ParseJSON(b []byte, filename string, opts Options) (map[string][]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v interface{}
+
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: parse JSON: %w", filename, err)
+	}
+
+	if err := d.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: parse JSON: unexpected data after the top-level object", filename)
+	}
+
+	data, err := fromValue(v, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return data, nil
+}
+
+// ParseYAML parses YAML mapping of key to value in the same way as ParseJSON.
+//
+// Scalar values are stored as they are written, without YAML typing, e.g. 0755, 1.10 and yes are stored as "0755",
+// "1.10" and "yes". Values tagged with !!binary are decoded as base64.
+func ParseYAML(b []byte, filename string, opts Options) (map[string][]byte, error) {
+	d := yaml3.NewDecoder(bytes.NewReader(b))
+
+	var doc yaml3.Node
+
+	if err := d.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: top level must be an object", filename)
+		}
+
+		return nil, fmt.Errorf("%s: parse YAML: %w", filename, err)
+	}
+
+	if err := d.Decode(&yaml3.Node{}); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: parse YAML: unexpected data after the top-level mapping", filename)
+	}
+
+	v, err := fromNode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	data, err := fromValue(v, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return data, nil
+}
+
+// fromNode converts YAML node to the same types as JSON decoded into interface{}, except that scalars other than null
+// are kept as written strings, and !!binary scalars are decoded as []byte
+func fromNode(n *yaml3.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml3.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+
+		return fromNode(n.Content[0])
+	case yaml3.AliasNode:
+		return fromNode(n.Alias)
+	case yaml3.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!binary":
+			b, err := base64.StdEncoding.DecodeString(n.Value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: decode !!binary value: %w", n.Line, err)
+			}
+
+			return b, nil
+		default:
+			return n.Value, nil
+		}
+	case yaml3.SequenceNode:
+		a := make([]interface{}, 0, len(n.Content))
+
+		for _, c := range n.Content {
+			v, err := fromNode(c)
+			if err != nil {
+				return nil, err
+			}
+
+			a = append(a, v)
+		}
+
+		return a, nil
+	case yaml3.MappingNode:
+		m := map[string]interface{}{}
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+
+			if k.Kind != yaml3.ScalarNode {
+				return nil, fmt.Errorf("line %d: key must be a scalar", k.Line)
+			}
+
+			if k.ShortTag() == "!!merge" {
+				return nil, fmt.Errorf("line %d: merge key << is not supported", k.Line)
+			}
+
+			if _, ok := m[k.Value]; ok {
+				return nil, fmt.Errorf("the key %s is defined more than once", k.Value)
+			}
+
+			value, err := fromNode(v)
+			if err != nil {
+				return nil, err
+			}
+
+			m[k.Value] = value
+		}
+
+		return m, nil
+	default:
+		return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
+	}
+}
+
+// EncodeJSON encodes data as indented JSON object. Values which are not valid UTF-8 are encoded as
+// {"base64": "..."}.
+func EncodeJSON(data map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	if err := e.Encode(toValue(data)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodeYAML encodes data as YAML mapping in the same way as EncodeJSON
+func EncodeYAML(data map[string][]byte) ([]byte, error) {
+	return yaml.Marshal(toValue(data))
+}
+
+func toValue(data map[string][]byte) map[string]interface{} {
+	m := make(map[string]interface{}, len(data))
+
+	for k, v := range data {
+		m[k] = EncodeValue(v)
+	}
+
+	return m
+}
+
+// EncodeValue returns v as string if it is valid UTF-8, otherwise as {"base64": "..."} object, which ParseJSON and
+// ParseYAML read back as the original bytes
+func EncodeValue(v []byte) interface{} {
+	if utf8.Valid(v) {
+		return string(v)
+	}
+
+	return map[string]string{
+		base64Field: base64.StdEncoding.EncodeToString(v),
+	}
+}
+
+func fromValue(v interface{}, opts Options) (map[string][]byte, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("top level must be an object")
+	}
+
+	data := map[string][]byte{}
+
+	if err := flatten(data, "", m, opts); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func flatten(data map[string][]byte, prefix string, m map[string]interface{}, opts Options) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	// sorted to return the same error for the same input
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + opts.Separator + k
+		}
+
+		var value []byte
+
+		switch v := m[k].(type) {
+		case string:
+			value = []byte(v)
+		case []byte:
+			value = v
+		case json.Number:
+			value = []byte(v.String())
+		case bool:
+			value = []byte(strconv.FormatBool(v))
+		case nil:
+			return fmt.Errorf("value of key %s is null", key)
+		case []interface{}:
+			return fmt.Errorf("value of key %s is an array, which cannot be loaded", key)
+		case map[string]interface{}:
+			if s, ok := v[base64Field].(string); ok && len(v) == 1 {
+				b, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return fmt.Errorf("decode value of key %s as base64-encoded string: %w", key, err)
+				}
+
+				value = b
+
+				break
+			}
+
+			if !opts.Flatten {
+				return fmt.Errorf("value of key %s is a nested object, which is loaded only if flattened", key)
+			}
+
+			if err := flatten(data, key, v, opts); err != nil {
+				return err
+			}
+
+			continue
+		default:
+			return fmt.Errorf("value of key %s has unsupported type %T", key, v)
+		}
+
+		if _, ok := data[key]; ok {
+			return fmt.Errorf("the key %s is defined more than once", key)
+		}
+
+		data[key] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package keyvalue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSON(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		opts     Options
+		wantData map[string][]byte
+		wantErr  error
+	}{
+		"strings, numbers and booleans": {
+			input: `{"database-url": "postgres://example.com:5432/dbname", "port": 5432, "ratio": 0.50, "debug": false, "private-key": "line1\nline2\n"}`,
+			wantData: map[string][]byte{
+				"database-url": []byte("postgres://example.com:5432/dbname"),
+				"port":         []byte("5432"),
+				"ratio":        []byte("0.50"),
+				"debug":        []byte("false"),
+				"private-key":  []byte("line1\nline2\n"),
+			},
+		},
+		"binary value": {
+			input: `{"logo.png": {"base64": "iVBORwA="}}`,
+			wantData: map[string][]byte{
+				"logo.png": {0x89, 'P', 'N', 'G', 0x00},
+			},
+		},
+		"flatten": {
+			input: `{"database": {"host": "example.com", "port": 5432, "password": {"base64": "cEBzcw=="}}, "debug": true}`,
+			opts: Options{
+				Flatten:   true,
+				Separator: "_",
+			},
+			wantData: map[string][]byte{
+				"database_host":     []byte("example.com"),
+				"database_port":     []byte("5432"),
+				"database_password": []byte("p@ss"),
+				"debug":             []byte("true"),
+			},
+		},
+		"nested object without flatten": {
+			input:   `{"database": {"host": "example.com"}}`,
+			wantErr: errors.New("test.json: value of key database is a nested object, which is loaded only if flattened"),
+		},
+		"conflict by flatten": {
+			input: `{"database": {"host": "example.com"}, "database.host": "example.org"}`,
+			opts: Options{
+				Flatten:   true,
+				Separator: ".",
+			},
+			wantErr: errors.New("test.json: the key database.host is defined more than once"),
+		},
+		"array": {
+			input:   `{"hosts": ["a", "b"]}`,
+			wantErr: errors.New("test.json: value of key hosts is an array, which cannot be loaded"),
+		},
+		"null": {
+			input:   `{"password": null}`,
+			wantErr: errors.New("test.json: value of key password is null"),
+		},
+		"invalid base64": {
+			input:   `{"logo.png": {"base64": "!!!"}}`,
+			wantErr: errors.New("test.json: decode value of key logo.png as base64-encoded string: illegal base64 data at input byte 0"),
+		},
+		"not object": {
+			input:   `["foo"]`,
+			wantErr: errors.New("test.json: top level must be an object"),
+		},
+		"trailing data": {
+			input:   `{"foo": "bar"} {}`,
+			wantErr: errors.New("test.json: parse JSON: unexpected data after the top-level object"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseJSON([]byte(tc.input), "test.json", tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(got, tc.wantData) {
+					t.Fatalf("want %q, got %q", tc.wantData, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		opts     Options
+		wantData map[string][]byte
+		wantErr  error
+	}{
+		"flatten": {
+			input: `database:
+  host: example.com
+  port: 5432
+private-key: |
+  line1
+  line2
+logo.png:
+  base64: iVBORwA=
+`,
+			opts: Options{Flatten: true, Separator: "."},
+			wantData: map[string][]byte{
+				"database.host": []byte("example.com"),
+				"database.port": []byte("5432"),
+				"private-key":   []byte("line1\nline2\n"),
+				"logo.png":      {0x89, 'P', 'N', 'G', 0x00},
+			},
+		},
+		"scalars as written": {
+			input: `octal: 0755
+float: 1.10
+exponent: 1e3
+bool: yes
+upper-bool: TRUE
+hex: 0x1F
+date: 2024-01-02
+quoted: "0755"
+yes: no
+anchor: &anchor 007
+alias: *anchor
+binary: !!binary iVBORwA=
+`,
+			wantData: map[string][]byte{
+				"octal":      []byte("0755"),
+				"float":      []byte("1.10"),
+				"exponent":   []byte("1e3"),
+				"bool":       []byte("yes"),
+				"upper-bool": []byte("TRUE"),
+				"hex":        []byte("0x1F"),
+				"date":       []byte("2024-01-02"),
+				"quoted":     []byte("0755"),
+				"yes":        []byte("no"),
+				"anchor":     []byte("007"),
+				"alias":      []byte("007"),
+				"binary":     {0x89, 'P', 'N', 'G', 0x00},
+			},
+		},
+		"null": {
+			input:   "password: ~\n",
+			wantErr: errors.New("test.yaml: value of key password is null"),
+		},
+		"array": {
+			input:   "hosts:\n- a\n- b\n",
+			wantErr: errors.New("test.yaml: value of key hosts is an array, which cannot be loaded"),
+		},
+		"duplicated key": {
+			input:   "password: foo\npassword: bar\n",
+			wantErr: errors.New("test.yaml: the key password is defined more than once"),
+		},
+		"empty": {
+			input:   "",
+			wantErr: errors.New("test.yaml: top level must be an object"),
+		},
+		"top level scalar": {
+			input:   "foo\n",
+			wantErr: errors.New("test.yaml: top level must be an object"),
+		},
+		"multiple documents": {
+			input:   "foo: bar\n---\nbaz: qux\n",
+			wantErr: errors.New("test.yaml: parse YAML: unexpected data after the top-level mapping"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseYAML([]byte(tc.input), "test.yaml", tc.opts)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if !reflect.DeepEqual(got, tc.wantData) {
+					t.Fatalf("want %q, got %q", tc.wantData, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data := map[string][]byte{
+		"database-url": []byte("postgres://example.com:5432/dbname?sslmode=require&timeout=5"),
+		"private-key":  []byte("line1\nline2\n"),
+		"logo.png":     {0x89, 'P', 'N', 'G', 0x00},
+	}
+
+	gotJSON, err := EncodeJSON(data)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	wantJSON := `{
+  "database-url": "postgres://example.com:5432/dbname?sslmode=require&timeout=5",
+  "logo.png": {
+    "base64": "iVBORwA="
+  },
+  "private-key": "line1\nline2\n"
+}
+`
+	if string(gotJSON) != wantJSON {
+		t.Fatalf("want %q, got %q", wantJSON, string(gotJSON))
+	}
+
+	gotYAML, err := EncodeYAML(data)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	wantYAML := `database-url: postgres://example.com:5432/dbname?sslmode=require&timeout=5
+logo.png:
+  base64: iVBORwA=
+private-key: |
+  line1
+  line2
+`
+	if string(gotYAML) != wantYAML {
+		t.Fatalf("want %q, got %q", wantYAML, string(gotYAML))
+	}
+
+	for name, parse := range map[string]func([]byte, string, Options) (map[string][]byte, error){
+		"JSON": ParseJSON,
+		"YAML": ParseYAML,
+	} {
+		b := gotJSON
+		if name == "YAML" {
+			b = gotYAML
+		}
+
+		got, err := parse(b, "test", Options{})
+		if err != nil {
+			t.Fatalf("%s: want no error, got %q", name, err.Error())
+		}
+
+		if !reflect.DeepEqual(got, data) {
+			t.Fatalf("%s: want %q, got %q", name, data, got)
+		}
+	}
+}
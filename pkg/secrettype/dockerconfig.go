@@ -0,0 +1,214 @@
+package secrettype
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultDockerRegistryServer is the server of Docker Hub used by docker login
+const DefaultDockerRegistryServer = "https://index.docker.io/v1/"
+
+// DockerConfigEntry represents the credentials of a registry in .dockerconfigjson
+type DockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	// Auth is base64-encoded "username:password"
+	Auth string `json:"auth,omitempty"`
+}
+
+// NewDockerConfigEntry returns the entry with username, password and auth set
+func NewDockerConfigEntry(username, password, email string) DockerConfigEntry {
+	return DockerConfigEntry{
+		Username: username,
+		Password: password,
+		Email:    email,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}
+}
+
+// DockerConfig is the content of .dockerconfigjson.
+// Fields other than the modified registries are kept as they are, including unknown ones.
+type DockerConfig struct {
+	fields map[string]json.RawMessage
+	auths  map[string]json.RawMessage
+}
+
+// NewDockerConfig returns the config without registries
+func NewDockerConfig() *DockerConfig {
+	return &DockerConfig{
+		fields: map[string]json.RawMessage{},
+		auths:  map[string]json.RawMessage{},
+	}
+}
+
+// ParseDockerConfig parses the content of .dockerconfigjson
+func ParseDockerConfig(b []byte) (*DockerConfig, error) {
+	c := NewDockerConfig()
+
+	if err := json.Unmarshal(b, &c.fields); err != nil {
+		return nil, fmt.Errorf("parse docker config: %w", err)
+	}
+
+	if c.fields == nil {
+		return nil, errors.New("parse docker config: must be a JSON object")
+	}
+
+	auths, ok := c.fields["auths"]
+	if !ok {
+		return nil, errors.New("parse docker config: auths does not exist")
+	}
+
+	if err := json.Unmarshal(auths, &c.auths); err != nil {
+		return nil, fmt.Errorf("parse auths of docker config: %w", err)
+	}
+
+	if c.auths == nil {
+		c.auths = map[string]json.RawMessage{}
+	}
+
+	for _, server := range c.Servers() {
+		if _, err := c.Entry(server); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Servers returns the sorted servers of registries
+func (c *DockerConfig) Servers() []string {
+	servers := make([]string, 0, len(c.auths))
+	for s := range c.auths {
+		servers = append(servers, s)
+	}
+
+	sort.Strings(servers)
+
+	return servers
+}
+
+// Entry returns the credentials of server
+func (c *DockerConfig) Entry(server string) (DockerConfigEntry, error) {
+	var e DockerConfigEntry
+
+	raw, ok := c.auths[server]
+	if !ok {
+		return e, fmt.Errorf("registry %s does not exist", server)
+	}
+
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return e, fmt.Errorf("parse credentials of registry %s: %w", server, err)
+	}
+
+	return e, nil
+}
+
+// Set adds the credentials of server, or replaces the existing ones
+func (c *DockerConfig) Set(server string, e DockerConfigEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode credentials of registry %s: %w", server, err)
+	}
+
+	c.auths[server] = b
+
+	return nil
+}
+
+// Remove removes the credentials of server. false is returned if the server does not exist.
+func (c *DockerConfig) Remove(server string) bool {
+	if _, ok := c.auths[server]; !ok {
+		return false
+	}
+
+	delete(c.auths, server)
+
+	return true
+}
+
+// Marshal returns the content of .dockerconfigjson
+func (c *DockerConfig) Marshal() ([]byte, error) {
+	auths, err := json.Marshal(c.auths)
+	if err != nil {
+		return nil, fmt.Errorf("encode auths of docker config: %w", err)
+	}
+
+	fields := make(map[string]json.RawMessage, len(c.fields)+1)
+	for k, v := range c.fields {
+		fields[k] = v
+	}
+
+	fields["auths"] = auths
+
+	return json.Marshal(fields)
+}
+
+// SummarizeDockerConfig returns the server, username and email of each registry with the auth masked
+func SummarizeDockerConfig(b []byte) ([]string, error) {
+	c, err := ParseDockerConfig(b)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]string, 0, len(c.auths))
+
+	for _, server := range c.Servers() {
+		e, err := c.Entry(server)
+		if err != nil {
+			return nil, err
+		}
+
+		username := e.Username
+		if username == "" && e.Auth != "" {
+			if auth, err := base64.StdEncoding.DecodeString(e.Auth); err == nil {
+				username, _, _ = strings.Cut(string(auth), ":")
+			}
+		}
+
+		var sb strings.Builder
+
+		fmt.Fprintf(&sb, "server=%s username=%s", server, username)
+
+		if e.Email != "" {
+			fmt.Fprintf(&sb, " email=%s", e.Email)
+		}
+
+		if e.Auth != "" || e.Password != "" {
+			sb.WriteString(" auth=********")
+		}
+
+		summaries = append(summaries, sb.String())
+	}
+
+	return summaries, nil
+}
+
+// validateDockerConfig checks that b is valid .dockerconfigjson and each registry has credentials
+func validateDockerConfig(b []byte) error {
+	c, err := ParseDockerConfig(bytes.TrimSpace(b))
+	if err != nil {
+		return err
+	}
+
+	for _, server := range c.Servers() {
+		e, err := c.Entry(server)
+		if err != nil {
+			return err
+		}
+
+		if e.Auth != "" {
+			auth, err := base64.StdEncoding.DecodeString(e.Auth)
+			if err != nil || !bytes.Contains(auth, []byte(":")) {
+				return fmt.Errorf("auth of registry %s must be base64-encoded username:password", server)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package secrettype
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDockerConfig(t *testing.T) {
+	c, err := ParseDockerConfig([]byte(`{"auths":{"ghcr.io":{"username":"dtan4","password":"p@ss","auth":"ZHRhbjQ6cEBzcw==","x-extra":true}},"credsStore":"desktop"}`))
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	if err := c.Set("quay.io", NewDockerConfigEntry("robot", "token", "robot@example.com")); err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	if want, got := []string{"ghcr.io", "quay.io"}, c.Servers(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("want servers %#v, got %#v", want, got)
+	}
+
+	if !c.Remove("ghcr.io") {
+		t.Fatal("want ghcr.io to be removed")
+	}
+
+	if c.Remove("docker.io") {
+		t.Fatal("want docker.io not to be removed")
+	}
+
+	b, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	want := `{"auths":{"quay.io":{"username":"robot","password":"token","email":"robot@example.com","auth":"cm9ib3Q6dG9rZW4="}},"credsStore":"desktop"}`
+	if string(b) != want {
+		t.Fatalf("want %s, got %s", want, string(b))
+	}
+}
+
+func TestDockerConfigKeepsOtherRegistries(t *testing.T) {
+	original := `{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw==","x-extra":true}}}`
+
+	c, err := ParseDockerConfig([]byte(original))
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	if err := c.Set("quay.io", NewDockerConfigEntry("robot", "token", "")); err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	b, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	want := `{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw==","x-extra":true},"quay.io":{"username":"robot","password":"token","auth":"cm9ib3Q6dG9rZW4="}}}`
+	if string(b) != want {
+		t.Fatalf("want %s, got %s", want, string(b))
+	}
+}
+
+func TestParseDockerConfig(t *testing.T) {
+	testcases := map[string]struct {
+		config  string
+		wantErr error
+	}{
+		"valid": {
+			config: `{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`,
+		},
+		"not JSON": {
+			config:  `auths`,
+			wantErr: errors.New("parse docker config: invalid character 'a' looking for beginning of value"),
+		},
+		"not object": {
+			config:  `null`,
+			wantErr: errors.New("parse docker config: must be a JSON object"),
+		},
+		"without auths": {
+			config:  `{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}`,
+			wantErr: errors.New("parse docker config: auths does not exist"),
+		},
+		"invalid entry": {
+			config:  `{"auths":{"ghcr.io":"dtan4"}}`,
+			wantErr: errors.New("parse credentials of registry ghcr.io: json: cannot unmarshal string into Go value of type secrettype.DockerConfigEntry"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseDockerConfig([]byte(tc.config))
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeDockerConfig(t *testing.T) {
+	got, err := SummarizeDockerConfig([]byte(`{"auths":{"quay.io":{"username":"robot","password":"token","email":"robot@example.com","auth":"cm9ib3Q6dG9rZW4="},"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`))
+	if err != nil {
+		t.Fatalf("want no error, got %q", err.Error())
+	}
+
+	want := []string{
+		"server=ghcr.io username=dtan4 auth=********",
+		"server=quay.io username=robot email=robot@example.com auth=********",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %#v, got %#v", want, got)
+	}
+}
@@ -0,0 +1,85 @@
+package secrettype
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Validate checks whether the secret has the keys and values required by its type.
+// now is used to check the expiry of certificates, which is not checked if now is zero.
+func Validate(secret *v1.Secret, now time.Time) error {
+	switch secret.Type {
+	case v1.SecretTypeBasicAuth:
+		// Both of them are required, though the API server accepts either of them
+		if _, err := requireKey(secret, v1.BasicAuthUsernameKey); err != nil {
+			return err
+		}
+
+		if _, err := requireKey(secret, v1.BasicAuthPasswordKey); err != nil {
+			return err
+		}
+	case v1.SecretTypeSSHAuth:
+		key, err := requireKey(secret, v1.SSHAuthPrivateKey)
+		if err != nil {
+			return err
+		}
+
+		return ValidateSSHPrivateKey(key)
+	case v1.SecretTypeTLS:
+		cert, err := requireKey(secret, v1.TLSCertKey)
+		if err != nil {
+			return err
+		}
+
+		key, err := requireKey(secret, v1.TLSPrivateKeyKey)
+		if err != nil {
+			return err
+		}
+
+		return ValidateTLS(cert, key, now)
+	case v1.SecretTypeDockerConfigJson:
+		config, err := requireKey(secret, v1.DockerConfigJsonKey)
+		if err != nil {
+			return err
+		}
+
+		return validateDockerConfig(config)
+	}
+
+	return nil
+}
+
+// Summary returns human-readable summaries of the value of key in secret with the given type.
+// A value may have multiple summaries, e.g. one for each registry in .dockerconfigjson.
+// false is returned if the value has no summary.
+func Summary(secretType v1.SecretType, key string, value []byte) ([]string, bool) {
+	switch {
+	case secretType == v1.SecretTypeTLS && key == v1.TLSCertKey:
+		s, err := SummarizeCertificates(value)
+		if err != nil {
+			return nil, false
+		}
+
+		return []string{s}, true
+	case secretType == v1.SecretTypeDockerConfigJson && key == v1.DockerConfigJsonKey:
+		ss, err := SummarizeDockerConfig(value)
+		if err != nil || len(ss) == 0 {
+			return nil, false
+		}
+
+		return ss, true
+	}
+
+	return nil, false
+}
+
+func requireKey(secret *v1.Secret, key string) ([]byte, error) {
+	v, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret of type %s must have key %s", secret.Type, key)
+	}
+
+	return v, nil
+}
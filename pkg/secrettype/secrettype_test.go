@@ -0,0 +1,110 @@
+package secrettype
+
+import (
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	testcases := map[string]struct {
+		secret  *v1.Secret
+		wantErr error
+	}{
+		"valid docker config": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					v1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"ZHRhbjQ6cEBzcw=="}}}`),
+				},
+			},
+		},
+		"docker config with invalid auth": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					v1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"dtan4"}}}`),
+				},
+			},
+			wantErr: errors.New("auth of registry ghcr.io must be base64-encoded username:password"),
+		},
+		"basic-auth": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					v1.BasicAuthUsernameKey: []byte("dtan4"),
+					v1.BasicAuthPasswordKey: []byte("p@ssw0rd"),
+				},
+			},
+		},
+		"basic-auth with username only": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					v1.BasicAuthUsernameKey: []byte("dtan4"),
+				},
+			},
+			wantErr: errors.New("secret of type kubernetes.io/basic-auth must have key password"),
+		},
+		"basic-auth with password only": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					v1.BasicAuthPasswordKey: []byte("p@ssw0rd"),
+				},
+			},
+			wantErr: errors.New("secret of type kubernetes.io/basic-auth must have key username"),
+		},
+		"basic-auth without username and password": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					"email": []byte("dtan4@example.com"),
+				},
+			},
+			wantErr: errors.New("secret of type kubernetes.io/basic-auth must have key username"),
+		},
+		"ssh-auth without private key": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeSSHAuth,
+				Data: map[string][]byte{
+					"known_hosts": []byte("github.com ssh-ed25519 AAAA"),
+				},
+			},
+			wantErr: errors.New("secret of type kubernetes.io/ssh-auth must have key ssh-privatekey"),
+		},
+		"ssh-auth with invalid private key": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeSSHAuth,
+				Data: map[string][]byte{
+					v1.SSHAuthPrivateKey: []byte("foo"),
+				},
+			},
+			wantErr: errors.New("invalid SSH private key: ssh: no key found"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tc.secret, testNow)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+		})
+	}
+}
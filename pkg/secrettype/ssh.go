@@ -0,0 +1,23 @@
+package secrettype
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ValidateSSHPrivateKey checks that b is a private key in OpenSSH or PEM format.
+// Keys encrypted with passphrase are accepted without decryption.
+func ValidateSSHPrivateKey(b []byte) error {
+	if _, err := ssh.ParseRawPrivateKey(b); err != nil {
+		var perr *ssh.PassphraseMissingError
+		if errors.As(err, &perr) {
+			return nil
+		}
+
+		return fmt.Errorf("invalid SSH private key: %w", err)
+	}
+
+	return nil
+}
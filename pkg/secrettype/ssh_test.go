@@ -0,0 +1,77 @@
+package secrettype
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestValidateSSHPrivateKey(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openSSH, err := ssh.MarshalPrivateKey(edKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := ssh.MarshalPrivateKeyWithPassphrase(edKey, "", []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := map[string]struct {
+		key     []byte
+		wantErr error
+	}{
+		"OpenSSH": {
+			key: pem.EncodeToMemory(openSSH),
+		},
+		"OpenSSH with passphrase": {
+			key: pem.EncodeToMemory(encrypted),
+		},
+		"PEM": {
+			key: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+		},
+		"public key": {
+			key:     []byte("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO5mXPPzH4+YSFi3WU0vOU3AG/WwHydNWDxRBYRnpRx3 dtan4@example.com"),
+			wantErr: errors.New("invalid SSH private key: ssh: no key found"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateSSHPrivateKey(tc.key)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+		})
+	}
+}
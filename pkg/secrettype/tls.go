@@ -0,0 +1,166 @@
+package secrettype
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCertificates parses PEM-encoded certificates, which must be ordered from leaf to root
+func ParseCertificates(b []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate #%d: %w", len(certs)+1, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+
+	return certs, nil
+}
+
+// ParsePrivateKey parses PEM-encoded private key in PKCS #1, PKCS #8 or SEC 1 (EC) format
+func ParsePrivateKey(b []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+
+		block, b = pem.Decode(b)
+		if block == nil {
+			return nil, errors.New("no PEM-encoded private key found")
+		}
+
+		var (
+			key interface{}
+			err error
+		)
+
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parse private key: %w", err)
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+
+		return signer, nil
+	}
+}
+
+// ValidateTLS checks that the private key matches the first certificate, each certificate is signed by the next one,
+// and all certificates are valid at now. Expiry is not checked if now is zero.
+func ValidateTLS(certPEM, keyPEM []byte, now time.Time) error {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	key, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(certs[0].PublicKey) {
+		return fmt.Errorf("private key does not match certificate %s", describeCertificate(certs[0]))
+	}
+
+	for i := 0; i+1 < len(certs); i++ {
+		if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			return fmt.Errorf("certificate %s is not signed by the next certificate %s, certificates must be ordered from leaf to root", describeCertificate(certs[i]), describeCertificate(certs[i+1]))
+		}
+	}
+
+	if now.IsZero() {
+		return nil
+	}
+
+	for _, cert := range certs {
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate %s has expired at %s", describeCertificate(cert), cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("certificate %s is not valid until %s", describeCertificate(cert), cert.NotBefore.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// SummarizeCertificates returns the subject, SANs, issuer and expiry of the first certificate
+func SummarizeCertificates(certPEM []byte) (string, error) {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return "", err
+	}
+
+	cert := certs[0]
+
+	sans := append([]string{}, cert.DNSNames...)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	sans = append(sans, cert.EmailAddresses...)
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	if len(sans) == 0 {
+		sans = []string{"<none>"}
+	}
+
+	summary := fmt.Sprintf("subject=%s sans=%s issuer=%s notAfter=%s",
+		strconv.Quote(cert.Subject.String()),
+		strings.Join(sans, ","),
+		strconv.Quote(cert.Issuer.String()),
+		cert.NotAfter.UTC().Format(time.RFC3339),
+	)
+
+	if len(certs) > 1 {
+		summary += fmt.Sprintf(" chain=%d", len(certs))
+	}
+
+	return summary, nil
+}
+
+func describeCertificate(cert *x509.Certificate) string {
+	return strconv.Quote(cert.Subject.String())
+}
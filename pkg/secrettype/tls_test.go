@@ -0,0 +1,311 @@
+package secrettype
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var testNow = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  []byte
+}
+
+// newTestCert issues a certificate valid from notBefore to notAfter, self-signed if parent is nil
+func newTestCert(t *testing.T, cn string, parent *testCert, notBefore, notAfter time.Time, dnsNames ...string) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		DNSNames:              dnsNames,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	issuer, signer := tmpl, crypto.Signer(key)
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func keyPEM(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestValidateTLS(t *testing.T) {
+	validFrom, validTo := testNow.Add(-24*time.Hour), testNow.Add(365*24*time.Hour)
+
+	ca := newTestCert(t, "Example CA", nil, validFrom, validTo)
+	leaf := newTestCert(t, "example.com", ca, validFrom, validTo, "example.com")
+	expired := newTestCert(t, "expired.example.com", ca, validFrom, testNow.Add(-time.Hour))
+	notYetValid := newTestCert(t, "future.example.com", ca, testNow.Add(time.Hour), validTo)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	ecDER, err := x509.MarshalECPrivateKey(leaf.key.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})
+
+	testcases := map[string]struct {
+		cert    []byte
+		key     []byte
+		wantErr error
+	}{
+		"leaf only": {
+			cert: leaf.pem,
+			key:  keyPEM(t, leaf.key),
+		},
+		"leaf and CA": {
+			cert: append(append([]byte{}, leaf.pem...), ca.pem...),
+			key:  keyPEM(t, leaf.key),
+		},
+		"EC private key": {
+			cert: leaf.pem,
+			key:  ecKeyPEM,
+		},
+		"no certificate": {
+			cert:    []byte("not a certificate"),
+			key:     keyPEM(t, leaf.key),
+			wantErr: errors.New("invalid certificate: no PEM-encoded certificate found"),
+		},
+		"no private key": {
+			cert:    leaf.pem,
+			key:     leaf.pem,
+			wantErr: errors.New("invalid private key: no PEM-encoded private key found"),
+		},
+		"key mismatch": {
+			cert:    leaf.pem,
+			key:     rsaKeyPEM,
+			wantErr: errors.New(`private key does not match certificate "CN=example.com"`),
+		},
+		"wrong chain order": {
+			cert:    append(append([]byte{}, ca.pem...), leaf.pem...),
+			key:     keyPEM(t, ca.key),
+			wantErr: errors.New(`certificate "CN=Example CA" is not signed by the next certificate "CN=example.com", certificates must be ordered from leaf to root`),
+		},
+		"expired": {
+			cert:    expired.pem,
+			key:     keyPEM(t, expired.key),
+			wantErr: errors.New(`certificate "CN=expired.example.com" has expired at 2025-12-31T23:00:00Z`),
+		},
+		"not yet valid": {
+			cert:    notYetValid.pem,
+			key:     keyPEM(t, notYetValid.key),
+			wantErr: errors.New(`certificate "CN=future.example.com" is not valid until 2026-01-01T01:00:00Z`),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateTLS(tc.cert, tc.key, testNow)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeCertificates(t *testing.T) {
+	validFrom, validTo := testNow.Add(-24*time.Hour), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ca := newTestCert(t, "Example CA", nil, validFrom, validTo)
+	leaf := newTestCert(t, "example.com", ca, validFrom, validTo, "example.com", "www.example.com")
+
+	testcases := map[string]struct {
+		cert    []byte
+		want    string
+		wantErr error
+	}{
+		"leaf only": {
+			cert: leaf.pem,
+			want: `subject="CN=example.com" sans=example.com,www.example.com issuer="CN=Example CA" notAfter=2027-01-01T00:00:00Z`,
+		},
+		"with chain": {
+			cert: append(append([]byte{}, leaf.pem...), ca.pem...),
+			want: `subject="CN=example.com" sans=example.com,www.example.com issuer="CN=Example CA" notAfter=2027-01-01T00:00:00Z chain=2`,
+		},
+		"without SANs": {
+			cert: ca.pem,
+			want: `subject="CN=Example CA" sans=<none> issuer="CN=Example CA" notAfter=2027-01-01T00:00:00Z`,
+		},
+		"not a certificate": {
+			cert:    []byte("foo"),
+			wantErr: errors.New("no PEM-encoded certificate found"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SummarizeCertificates(tc.cert)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+
+				if got != tc.want {
+					t.Fatalf("want %q, got %q", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	validFrom, validTo := testNow.Add(-24*time.Hour), testNow.Add(365*24*time.Hour)
+	leaf := newTestCert(t, "example.com", nil, validFrom, validTo)
+	expired := newTestCert(t, "expired.example.com", nil, validFrom, testNow.Add(-time.Hour))
+
+	testcases := map[string]struct {
+		secret  *v1.Secret
+		now     time.Time
+		wantErr error
+	}{
+		"opaque": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeOpaque,
+				Data: map[string][]byte{"foo": []byte("bar")},
+			},
+		},
+		"valid TLS": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					v1.TLSCertKey:       leaf.pem,
+					v1.TLSPrivateKeyKey: keyPEM(t, leaf.key),
+				},
+			},
+		},
+		"expired TLS": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					v1.TLSCertKey:       expired.pem,
+					v1.TLSPrivateKeyKey: keyPEM(t, expired.key),
+				},
+			},
+			now:     testNow,
+			wantErr: errors.New(`certificate "CN=expired.example.com" has expired at 2025-12-31T23:00:00Z`),
+		},
+		"expired TLS without checking expiry": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					v1.TLSCertKey:       expired.pem,
+					v1.TLSPrivateKeyKey: keyPEM(t, expired.key),
+				},
+			},
+		},
+		"TLS without key": {
+			secret: &v1.Secret{
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					v1.TLSCertKey: leaf.pem,
+				},
+			},
+			wantErr: errors.New("secret of type kubernetes.io/tls must have key tls.key"),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tc.secret, tc.now)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("want error %q, got no error", tc.wantErr.Error())
+				}
+
+				if err.Error() != tc.wantErr.Error() {
+					t.Fatalf("want error %q, got %q", tc.wantErr.Error(), err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("want no error, got %q", err.Error())
+				}
+			}
+		})
+	}
+}